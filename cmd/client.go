@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clientTimeout      time.Duration
+	clientProxy        string
+	clientInsecure     bool
+	clientCAFile       string
+	clientCertFile     string
+	clientKeyFile      string
+	clientNoFollow     bool
+	clientMaxRedirects int
+	clientHTTP2        bool
+)
+
+func addClientFlags(c *cobra.Command) {
+	f := c.Flags()
+	f.DurationVar(&clientTimeout, "timeout", 0, "HTTP timeout per request (default 15s)")
+	f.StringVar(&clientProxy, "proxy", "", "Send requests through this proxy URL (default: HTTP_PROXY/HTTPS_PROXY)")
+	f.BoolVarP(&clientInsecure, "insecure", "k", false, "Skip TLS certificate verification")
+	f.StringVar(&clientCAFile, "cacert", "", "Also trust the CA certificates in this PEM file")
+	f.StringVar(&clientCertFile, "cert", "", "Client certificate (PEM) for mutual TLS")
+	f.StringVar(&clientKeyFile, "key", "", "Private key (PEM) of --cert, if not in the same file")
+	f.BoolVar(&clientNoFollow, "no-follow", false, "Do not follow redirects")
+	f.IntVar(&clientMaxRedirects, "max-redirects", 0, "Follow at most this many redirects (default 10)")
+	f.BoolVar(&clientHTTP2, "http2", true, "Negotiate HTTP/2 with servers that support it")
+}
+
+// clientOptions returns the HTTP client settings given on the command line.
+// They apply to every suite; suites and requests may override them.
+func clientOptions(cmd *cobra.Command) (domain.ClientOptions, error) {
+	var o domain.ClientOptions
+	f := cmd.Flags()
+	if clientTimeout < 0 {
+		return o, fmt.Errorf("--timeout must not be negative")
+	}
+	if clientMaxRedirects < 0 {
+		return o, fmt.Errorf("--max-redirects must not be negative")
+	}
+	o.Timeout = domain.Duration(clientTimeout)
+	o.MaxRedirects = clientMaxRedirects
+	o.Proxy = clientProxy
+	if f.Changed("insecure") {
+		o.InsecureSkipVerify = &clientInsecure
+	}
+	if f.Changed("no-follow") {
+		follow := !clientNoFollow
+		o.FollowRedirects = &follow
+	}
+	if f.Changed("http2") {
+		o.HTTP2 = &clientHTTP2
+	}
+	if clientKeyFile != "" && clientCertFile == "" {
+		return o, fmt.Errorf("--key requires --cert")
+	}
+	// Paths on the command line are relative to the working directory,
+	// not to the suite.
+	for dst, src := range map[*string]string{&o.CAFile: clientCAFile, &o.CertFile: clientCertFile, &o.KeyFile: clientKeyFile} {
+		if src == "" {
+			continue
+		}
+		abs, err := filepath.Abs(src)
+		if err != nil {
+			return o, err
+		}
+		*dst = abs
+	}
+	return o, nil
+}
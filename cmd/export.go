@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/curl"
+	"github.com/IsmailCLN/tapir/internal/parser"
+	"github.com/IsmailCLN/tapir/internal/runner"
+	"github.com/IsmailCLN/tapir/internal/sharedcontext"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportSuite   string
+	exportRequest string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert suites into other formats",
+}
+
+var exportCurlCmd = &cobra.Command{
+	Use:   "curl <suite.yaml>",
+	Short: "Print the curl equivalent of each request",
+	Long: "Builds every request exactly as 'tapir run' would and prints it as a curl\n" +
+		"command. Variables resolve from --env/--var or their ${name:-default}; values\n" +
+		"that are only known at run time (captures) are left as ${name}. Values read\n" +
+		"with ${env:NAME} are printed as \"${NAME}\" for the shell to fill in; other\n" +
+		"credentials derived from secrets are printed as ****. OAuth2 tokens are\n" +
+		"not fetched; the Authorization header carries <oauth2-token> instead.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		suites, err := parser.LoadTestSuite(args[0])
+		if err != nil {
+			return err
+		}
+		vars, store, err := loadVars(args[0])
+		if err != nil {
+			return err
+		}
+		client, err := clientOptions(cmd)
+		if err != nil {
+			return err
+		}
+		shared := sharedcontext.New()
+		shared.SetAll(vars)
+		shared.UseSecrets(store)
+
+		// printing a request must not send credentials anywhere
+		ctx := runner.WithoutTokenFetch(cmd.Context())
+		out := cmd.OutOrStdout()
+		n := 0
+		for i := range suites {
+			s := &suites[i]
+			s.ClientOptions = s.ClientOptions.Or(client)
+			if exportSuite != "" && s.Name != exportSuite {
+				continue
+			}
+			for _, r := range s.Requests {
+				if exportRequest != "" && r.Name != exportRequest {
+					continue
+				}
+				req, body, err := runner.BuildRequest(ctx, s, r, shared)
+				if err != nil {
+					return fmt.Errorf("%s/%s: %s", s.Name, r.Name, store.Redact(err.Error()))
+				}
+				if n > 0 {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintf(out, "# %s / %s\n%s\n", s.Name, r.Name, store.Redact(curl.FormatEnv(req.Method, req.URL.String(), req.Header, body, store.Names())))
+				n++
+			}
+		}
+		if n == 0 {
+			return fmt.Errorf("no matching requests in %s", args[0])
+		}
+		return nil
+	},
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/curl"
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/har"
+	"github.com/IsmailCLN/tapir/internal/openapi"
+	"github.com/IsmailCLN/tapir/internal/parser"
+	"github.com/IsmailCLN/tapir/internal/postman"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importOut   string
+	importForce bool
+
+	postmanEnv string
+
+	harHosts        []string
+	harMethods      []string
+	harContentTypes []string
+	harSnapshot     bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Generate test suites from other formats",
+}
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi <spec.yaml>",
+	Short: "Generate a suite with one request per OpenAPI 3 operation",
+	Long: "Reads an OpenAPI 3.x document and writes a suite per tag. Requests use example\n" +
+		"bodies and parameters, expect the declared success status and validate the\n" +
+		"response body against the declared JSON schema.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		doc, err := openapi.Load(spec)
+		if err != nil {
+			return err
+		}
+
+		suites := doc.Suites(openapi.ImportOptions{SchemaPath: relativeTo(importOut, spec)})
+		return writeImported(cmd, "openapi "+spec, suites)
+	},
+}
+
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman <collection.json>",
+	Short: "Convert a Postman v2.1 collection into suites",
+	Long: "Folders become suites, {{var}} becomes ${var} (defaulting to the collection or\n" +
+		"--env value) and simple pm.test status checks become status expectations.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		coll, err := postman.LoadCollection(args[0])
+		if err != nil {
+			return err
+		}
+		var env *postman.Environment
+		if postmanEnv != "" {
+			if env, err = postman.LoadEnvironment(postmanEnv); err != nil {
+				return err
+			}
+		}
+		return writeImported(cmd, "postman "+args[0], postman.Convert(coll, env))
+	},
+}
+
+var importHARCmd = &cobra.Command{
+	Use:   "har <capture.har>",
+	Short: "Turn a browser/proxy HAR recording into a regression suite",
+	Long: "Each recorded entry becomes a request that expects the recorded status and\n" +
+		"content type. Entries are grouped into one suite per host. Cookie and Authorization\n" +
+		"headers are not imported; add an auth block to the suite instead.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := har.Load(args[0])
+		if err != nil {
+			return err
+		}
+		suites := har.Convert(f, har.Options{
+			Hosts:        harHosts,
+			Methods:      harMethods,
+			ContentTypes: harContentTypes,
+			Snapshot:     harSnapshot,
+		})
+		if len(suites) == 0 {
+			return fmt.Errorf("no entries in %s matched the filters", args[0])
+		}
+		return writeImported(cmd, "har "+args[0], suites)
+	},
+}
+
+var importCurlCmd = &cobra.Command{
+	Use:   "curl [file|-] | curl -- <curl args...>",
+	Short: "Convert one or more curl command lines into a suite",
+	Long: "Reads curl commands from a file, from stdin (-) or from the arguments after --.\n" +
+		"Supports -X, -H, -d/--data-raw/--data-binary/--data-urlencode/--json, -u, -F,\n" +
+		"-b/--cookie, -G and -I. Each command becomes one request in a suite named \"curl\".",
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			text   string
+			source = "curl"
+		)
+		switch {
+		case cmd.ArgsLenAtDash() >= 0:
+			quoted := make([]string, len(args))
+			for i, a := range args {
+				quoted[i] = curl.Quote(a)
+			}
+			text = "curl " + strings.Join(quoted, " ")
+		case len(args) == 1:
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+				source += " " + args[0]
+			}
+			if err != nil {
+				return err
+			}
+			text = string(data)
+		default:
+			return fmt.Errorf("please provide a file, - for stdin, or the curl arguments after --")
+		}
+
+		reqs, err := curl.ParseAll(text)
+		if err != nil {
+			return err
+		}
+		// curl resolves files against the working directory, suites against their own.
+		for i := range reqs {
+			r := &reqs[i].Req
+			if r.BodyFile != "" {
+				r.BodyFile = relativeTo(importOut, r.BodyFile)
+			}
+			for j := range r.Multipart {
+				if f := r.Multipart[j].File; f != "" {
+					r.Multipart[j].File = relativeTo(importOut, f)
+				}
+			}
+		}
+		return writeImported(cmd, source, []domain.TestSuite{{Name: "curl", Requests: reqs}})
+	},
+}
+
+// writeImported writes suites to --out (stdout when "-") with a provenance header.
+func writeImported(cmd *cobra.Command, source string, suites []domain.TestSuite) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by tapir import %s at %s\n", source, time.Now().Format(time.RFC3339))
+	if err := parser.WriteTestSuites(&buf, suites); err != nil {
+		return fmt.Errorf("failed to encode suites: %w", err)
+	}
+
+	if importOut == "-" {
+		_, err := cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+
+	out := filepath.Clean(importOut)
+	if st, err := os.Stat(out); err == nil && st.Mode().IsRegular() && !importForce {
+		return fmt.Errorf("file already exists: %s (use --force to overwrite)", out)
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write suite: %w", err)
+	}
+
+	n := 0
+	for _, s := range suites {
+		n += len(s.Requests)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d requests in %d suites to %s\n", n, len(suites), out)
+	return nil
+}
+
+// relativeTo expresses target relative to the directory of the output file,
+// so references keep working when the suite is loaded from there.
+func relativeTo(out, target string) string {
+	dir := "."
+	if out != "-" {
+		dir = filepath.Dir(out)
+	}
+	absDir, err1 := filepath.Abs(dir)
+	absTarget, err2 := filepath.Abs(target)
+	if err1 != nil || err2 != nil {
+		return target
+	}
+	rel, err := filepath.Rel(absDir, absTarget)
+	if err != nil {
+		return absTarget
+	}
+	return filepath.ToSlash(rel)
+}
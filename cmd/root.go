@@ -23,11 +23,42 @@ func Execute() {
 
 func init() {
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importOpenAPICmd)
+	importCmd.AddCommand(importPostmanCmd)
+	importCmd.AddCommand(importHARCmd)
+	importCmd.AddCommand(importCurlCmd)
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportCurlCmd)
 
 	runCmd.Flags().StringVarP(&file, "file", "f", "", "Path to a YAML test-suite")
+	runCmd.Flags().BoolVar(&noTUI, "no-tui", false, "Stream plain result lines instead of the TUI and exit non-zero on failure (automatic when stdout is not a terminal)")
+	runCmd.Flags().BoolVar(&noTUI, "ci", false, "Alias for --no-tui")
+	runCmd.Flags().StringArrayVar(&reportSpecs, "report", nil, "Write a report as format=path, e.g. junit=results.xml (repeatable)")
+	runCmd.Flags().StringVar(&openapiSpec, "openapi", "", "Check every response against this OpenAPI 3 spec")
+
+	for _, c := range []*cobra.Command{runCmd, exportCurlCmd} {
+		c.Flags().StringVarP(&envName, "env", "e", "", "Environment profile to load, e.g. staging")
+		c.Flags().StringVar(&envFile, "env-file", "", "Environment file (default: tapir.env.yaml next to the suite or in the working directory)")
+		c.Flags().StringArrayVar(&varFlags, "var", nil, "Set a template variable as key=value (repeatable, overrides the profile)")
+		c.Flags().StringArrayVar(&dotenvFiles, "dotenv", nil, "Load ${env:NAME} values from this .env file (repeatable; default: .env in the working directory and next to the suite)")
+		addClientFlags(c)
+	}
 
 	initCmd.Flags().StringVarP(&initOut, "out", "o", "test-suites/sample.yaml", "Output YAML path")
 	initCmd.Flags().StringVarP(&initSuite, "name", "n", "sample", "Suite name")
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Overwrite if file exists")
+
+	importCmd.PersistentFlags().StringVarP(&importOut, "out", "o", "-", "Output YAML path (- for stdout)")
+	importCmd.PersistentFlags().BoolVarP(&importForce, "force", "f", false, "Overwrite if file exists")
+	importPostmanCmd.Flags().StringVar(&postmanEnv, "env", "", "Postman environment export to take variable defaults from")
+	importHARCmd.Flags().StringSliceVar(&harHosts, "host", nil, "Only import entries for these hosts")
+	importHARCmd.Flags().StringSliceVar(&harMethods, "method", nil, "Only import entries with these HTTP methods")
+	importHARCmd.Flags().StringSliceVar(&harContentTypes, "content-type", nil, "Only import responses whose media type contains one of these (e.g. json)")
+	importHARCmd.Flags().BoolVar(&harSnapshot, "snapshot", false, "Also pin the recorded response body with expect_body_equals")
+
+	exportCurlCmd.Flags().StringVarP(&exportSuite, "suite", "s", "", "Only export requests of this suite")
+	exportCurlCmd.Flags().StringVarP(&exportRequest, "request", "r", "", "Only export the request with this name")
 }
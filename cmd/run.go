@@ -2,13 +2,36 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
 
+	"github.com/IsmailCLN/tapir/internal/openapi"
 	"github.com/IsmailCLN/tapir/internal/parser"
+	"github.com/IsmailCLN/tapir/internal/profile"
+	"github.com/IsmailCLN/tapir/internal/report"
+	"github.com/IsmailCLN/tapir/internal/runner"
+	"github.com/IsmailCLN/tapir/internal/secrets"
+	"github.com/IsmailCLN/tapir/internal/templating"
 	"github.com/IsmailCLN/tapir/internal/ui"
+	"github.com/IsmailCLN/tapir/internal/validate"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
-var file string
+var (
+	file        string
+	openapiSpec string
+	noTUI       bool
+	reportSpecs []string
+
+	envName  string
+	envFile  string
+	varFlags []string
+
+	dotenvFiles []string
+)
 
 var runCmd = &cobra.Command{
 	Use:   "run [suite.yaml]",
@@ -23,9 +46,182 @@ var runCmd = &cobra.Command{
 			path = args[0]
 		}
 
+		if err := preflight(cmd.ErrOrStderr(), path); err != nil {
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+			return err
+		}
 		if _, err := parser.LoadTestSuite(path); err != nil {
 			return err
 		}
-		return ui.RenderStream([]string{path})
+
+		var reports []report.Spec
+		for _, r := range reportSpecs {
+			spec, err := report.ParseSpec(r)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, spec)
+		}
+
+		vars, store, err := loadVars(path)
+		if err != nil {
+			return err
+		}
+		client, err := clientOptions(cmd)
+		if err != nil {
+			return err
+		}
+		opts := runner.Options{Vars: vars, Secrets: store, Client: client}
+		var checker *openapi.Checker
+		if openapiSpec != "" {
+			doc, err := openapi.Load(openapiSpec)
+			if err != nil {
+				return err
+			}
+			checker = openapi.NewChecker(doc)
+			opts.Inspect = conformanceInspector(checker)
+		}
+
+		if !noTUI && isTerminal(os.Stdout) {
+			if err := ui.RenderStream([]string{path}, opts, reports); err != nil {
+				return err
+			}
+			if checker != nil {
+				printCoverage(cmd.OutOrStdout(), checker.Coverage())
+			}
+			return nil
+		}
+
+		// From here on, errors are test outcomes rather than usage mistakes.
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		sum, err := ui.RunPlain(ctx, cmd.OutOrStdout(), []string{path}, opts, reports)
+		if err != nil {
+			return err
+		}
+		if checker != nil {
+			printCoverage(cmd.OutOrStdout(), checker.Coverage())
+		}
+		if sum.Failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", sum.Failed, sum.Passed+sum.Failed)
+		}
+		return nil
 	},
 }
+
+// loadVars collects the template variables for a run of the suite at
+// suitePath: the --env profile from --env-file (or the tapir.env.yaml found
+// next to the suite), overridden by --var flags. The returned store resolves
+// ${env:NAME} from the environment and the .env files; profile and --var
+// values may use it too.
+func loadVars(suitePath string) (map[string]string, *secrets.Store, error) {
+	store := secrets.New()
+	files := dotenvFiles
+	if len(files) == 0 {
+		files = defaultDotenvFiles(suitePath)
+	}
+	for _, f := range files {
+		if err := store.LoadDotenv(f); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	vars := map[string]string{}
+	if envName != "" {
+		path := envFile
+		if path == "" {
+			if path = profile.Find(suitePath); path == "" {
+				return nil, nil, fmt.Errorf("--env %s: no %s next to %s or in the working directory (use --env-file)",
+					envName, profile.DefaultFile, suitePath)
+			}
+		}
+		f, err := profile.Load(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		p, err := f.Get(envName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		vars = p.Variables(envName)
+	}
+	for _, kv := range varFlags {
+		k, v, err := profile.ParseVar(kv)
+		if err != nil {
+			return nil, nil, err
+		}
+		vars[k] = v
+	}
+	return templating.ExpandStrings(vars, store), store, nil
+}
+
+// defaultDotenvFiles returns the .env files that exist in the working
+// directory and next to the suite, in that order so the suite's one wins.
+func defaultDotenvFiles(suitePath string) []string {
+	var files []string
+	seen := map[string]bool{}
+	for _, dir := range []string{".", filepath.Dir(suitePath)} {
+		p := filepath.Join(dir, ".env")
+		abs, err := filepath.Abs(p)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		if st, err := os.Stat(p); err == nil && st.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// preflight validates the suite file and prints every issue found, so a
+// broken suite fails before anything is sent.
+func preflight(w io.Writer, path string) error {
+	issues, err := validate.File(path)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	for _, is := range issues {
+		fmt.Fprintln(w, is)
+	}
+	return fmt.Errorf("%s: %d problem(s) found, nothing was sent", path, len(issues))
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// conformanceInspector reports every deviation from the spec as an
+// openapi_conformance result.
+func conformanceInspector(c *openapi.Checker) func(runner.Exchange) []runner.Result {
+	return func(ex runner.Exchange) []runner.Result {
+		var results []runner.Result
+		for _, err := range c.Check(ex.HTTPReq.Method, ex.HTTPReq.URL, ex.HTTPResp.StatusCode, ex.HTTPResp.Header, ex.Body) {
+			results = append(results, runner.Result{
+				Suite:    ex.Suite,
+				Request:  ex.Request,
+				Status:   runner.StatusFailed,
+				Err:      err,
+				TestName: "openapi_conformance",
+			})
+		}
+		return results
+	}
+}
+
+func printCoverage(w io.Writer, cov openapi.Coverage) {
+	fmt.Fprintf(w, "OpenAPI coverage: %d/%d operations exercised\n", cov.Exercised, cov.Total)
+	if len(cov.Missing) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Not exercised:")
+	for _, op := range cov.Missing {
+		fmt.Fprintf(w, "  %-7s %s\n", op.Method, op.Path)
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <suite.yaml>...",
+	Short: "Check suite files without sending any request",
+	Long: "Checks suite files for unknown keys, kwargs that do not match the assertion, unknown\n" +
+		"expectation types, duplicate request names, depends_on cycles and malformed URLs.\n" +
+		"Problems are printed as file:line:col: message and make the command exit non-zero,\n" +
+		"so it can run as a pre-commit hook.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+
+		var problems, bad int
+		for _, path := range args {
+			issues, err := validate.File(path)
+			if err != nil {
+				return err
+			}
+			for _, is := range issues {
+				fmt.Fprintln(cmd.OutOrStdout(), is)
+			}
+			if len(issues) > 0 {
+				problems += len(issues)
+				bad++
+			}
+		}
+		if problems > 0 {
+			return fmt.Errorf("%d problem(s) in %d of %d file(s)", problems, bad, len(args))
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%d file(s) OK\n", len(args))
+		return nil
+	},
+}
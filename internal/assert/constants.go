@@ -1,11 +1,14 @@
 package assert
 
 const (
-	keyInjectedHeaders = "headers"
-	keyHeaderName      = "header"
-	keyExpectedValue   = "value"
-	keyStatus          = "status_code"
-	keyMin             = "min"
-	keyMax             = "max"
-	keyExpectedStatus  = "code"
+	keyInjectedHeaders      = "headers"
+	keyInjectedSuiteDir     = "suite_dir"
+	keyInjectedResponseTime = "response_time"
+	keyInjectedTTFB         = "ttfb"
+	keyHeaderName           = "header"
+	keyExpectedValue        = "value"
+	keyStatus               = "status_code"
+	keyMin                  = "min"
+	keyMax                  = "max"
+	keyExpectedStatus       = "code"
 )
@@ -22,4 +22,8 @@ func expectBodyContains(body []byte, kw map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_body_contains", expectBodyContains) }
+func init() {
+	Register("expect_body_contains", expectBodyContains,
+		required("value", TypeString),
+	)
+}
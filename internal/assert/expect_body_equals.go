@@ -25,4 +25,8 @@ func expectBodyEquals(body []byte, kw map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_body_equals", expectBodyEquals) }
+func init() {
+	Register("expect_body_equals", expectBodyEquals,
+		required("value", TypeString),
+	)
+}
@@ -47,4 +47,10 @@ func expectContentType(_ []byte, kw map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_content_type_matches", expectContentType) }
+func init() {
+	Register("expect_content_type_matches", expectContentType,
+		required("value", TypeString),
+		optional("ignore_params", TypeBool),
+		optional("ignore_case", TypeBool),
+	)
+}
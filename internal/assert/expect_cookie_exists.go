@@ -7,7 +7,11 @@ import (
 )
 
 func init() {
-	Register("expect_cookie_exists", expectCookieExists)
+	Register("expect_cookie_exists", expectCookieExists,
+		oneOf("cookie", "cookieName", TypeString),
+		oneOf("cookie", "cookie_name", TypeString),
+		optional("ignore_case", TypeBool),
+	)
 }
 
 func expectCookieExists(_ []byte, kwargs map[string]any) error {
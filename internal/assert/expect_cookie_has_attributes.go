@@ -9,7 +9,19 @@ import (
 	"github.com/IsmailCLN/tapir/internal/helpers"
 )
 
-func init() { Register("expect_cookie_has_attributes", expectCookieHasAttributes) }
+func init() {
+	Register("expect_cookie_has_attributes", expectCookieHasAttributes,
+		oneOf("cookie", "cookieName", TypeString),
+		oneOf("cookie", "name", TypeString),
+		optional("path", TypeString),
+		optional("domain", TypeString),
+		optional("http_only", TypeBool),
+		optional("secure", TypeBool),
+		optional("samesite", TypeString),
+		optional("min_max_age", TypeInt),
+		optional("not_expired", TypeBool),
+	)
+}
 
 func expectCookieHasAttributes(_ []byte, kwargs map[string]any) error {
 	name, err := getCookieNameCompat(kwargs)
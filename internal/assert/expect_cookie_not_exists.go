@@ -6,7 +6,12 @@ import (
 	"strings"
 )
 
-func init() { Register("expect_cookie_not_exists", expectCookieNotExists) }
+func init() {
+	Register("expect_cookie_not_exists", expectCookieNotExists,
+		oneOf("cookie", "cookieName", TypeString),
+		oneOf("cookie", "cookie_name", TypeString),
+	)
+}
 
 func expectCookieNotExists(_ []byte, kwargs map[string]any) error {
 	name, err := getCookieName(kwargs)
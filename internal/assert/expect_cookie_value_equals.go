@@ -6,7 +6,13 @@ import (
 	"strings"
 )
 
-func init() { Register("expect_cookie_value_equals", expectCookieValueEquals) }
+func init() {
+	Register("expect_cookie_value_equals", expectCookieValueEquals,
+		oneOf("cookie", "cookieName", TypeString),
+		oneOf("cookie", "cookie_name", TypeString),
+		required("value", TypeString),
+	)
+}
 
 func expectCookieValueEquals(_ []byte, kwargs map[string]any) error {
 	name, err := getCookieName(kwargs)
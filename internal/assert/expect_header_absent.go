@@ -23,4 +23,8 @@ func expectHeaderAbsent(_ []byte, kw map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_header_absent", expectHeaderAbsent) }
\ No newline at end of file
+func init() {
+	Register("expect_header_absent", expectHeaderAbsent,
+		required("header", TypeString),
+	)
+}
\ No newline at end of file
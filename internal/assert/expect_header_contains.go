@@ -58,4 +58,10 @@ func expectHeaderContains(_ []byte, kw map[string]any) error {
 	return fmt.Errorf("header %s does not contain %q; got: %q", name, needle, strings.Join(values, ", "))
 }
 
-func init() { Register("expect_header_contains", expectHeaderContains) }
+func init() {
+	Register("expect_header_contains", expectHeaderContains,
+		required("header", TypeString),
+		required("value", TypeString),
+		optional("ignore_case", TypeBool),
+	)
+}
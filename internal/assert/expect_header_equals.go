@@ -46,4 +46,10 @@ func expectHeaderEquals(_ []byte, kw map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_header_equals", expectHeaderEquals) }
+func init() {
+	Register("expect_header_equals", expectHeaderEquals,
+		required("header", TypeString),
+		required("value", TypeString),
+		optional("ignore_case", TypeBool),
+	)
+}
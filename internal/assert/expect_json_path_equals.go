@@ -0,0 +1,34 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_equals: checks that the value at a JSON path equals the expected value.
+// Kwargs:
+//
+//	path:  string (required)
+//	value: any    (required) -> scalars, lists or maps; numbers compare by value
+//	match: all|any (optional)
+func expectJSONPathEquals(body []byte, kw map[string]any) error {
+	want, ok := kw[keyExpectedValue]
+	if !ok {
+		return fmt.Errorf("expect_json_path_equals: missing %q", keyExpectedValue)
+	}
+	return checkJSONPath("expect_json_path_equals", body, kw, func(got any) error {
+		if !jsonpath.Equal(got, want) {
+			return fmt.Errorf("got=%s, want=%s", jsonpath.Format(got), jsonpath.Format(want))
+		}
+		return nil
+	})
+}
+
+func init() {
+	Register("expect_json_path_equals", expectJSONPathEquals,
+		required("path", TypeString),
+		required("value", TypeAny),
+		optional("match", TypeString),
+	)
+}
@@ -0,0 +1,24 @@
+package assert
+
+import "fmt"
+
+// expect_json_path_exists: checks that a JSON path matches at least one value (null included).
+// Kwargs:
+//
+//	path: string (required)
+func expectJSONPathExists(body []byte, kw map[string]any) error {
+	p, vals, err := jsonPathMatches("expect_json_path_exists", body, kw)
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 {
+		return fmt.Errorf("path %s not found", p)
+	}
+	return nil
+}
+
+func init() {
+	Register("expect_json_path_exists", expectJSONPathExists,
+		required("path", TypeString),
+	)
+}
@@ -0,0 +1,36 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_in: checks that the value at a JSON path is one of the allowed values.
+// Kwargs:
+//
+//	path:   string (required)
+//	values: list   (required)
+//	match:  all|any (optional)
+func expectJSONPathIn(body []byte, kw map[string]any) error {
+	allowed, ok := kw["values"].([]any)
+	if !ok || len(allowed) == 0 {
+		return fmt.Errorf("expect_json_path_in: %q must be a non-empty list", "values")
+	}
+	return checkJSONPath("expect_json_path_in", body, kw, func(got any) error {
+		for _, a := range allowed {
+			if jsonpath.Equal(got, a) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not in allowed set %s", jsonpath.Format(got), jsonpath.Format(allowed))
+	})
+}
+
+func init() {
+	Register("expect_json_path_in", expectJSONPathIn,
+		required("path", TypeString),
+		required("values", TypeList),
+		optional("match", TypeString),
+	)
+}
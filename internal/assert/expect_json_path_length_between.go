@@ -0,0 +1,66 @@
+package assert
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_length_between: checks the length of an array, string or object.
+// For paths that can match several values (wildcards, filters, ..) the number
+// of matches is checked instead, e.g. items[?(@.active)].
+// Kwargs:
+//
+//	path: string (required)
+//	min:  int    (optional, default 0)
+//	max:  int    (optional, default unbounded)
+func expectJSONPathLengthBetween(body []byte, kw map[string]any) error {
+	min, hasMin := helpers.GetInt(kw, keyMin)
+	max, hasMax := helpers.GetInt(kw, keyMax)
+	if !hasMin && !hasMax {
+		return fmt.Errorf("expect_json_path_length_between: at least one of %q or %q is required", keyMin, keyMax)
+	}
+
+	p, vals, err := jsonPathMatches("expect_json_path_length_between", body, kw)
+	if err != nil {
+		return err
+	}
+
+	var n int
+	if !p.Definite() {
+		n = len(vals)
+	} else {
+		if len(vals) == 0 {
+			return fmt.Errorf("path %s not found", p)
+		}
+		switch t := vals[0].(type) {
+		case []any:
+			n = len(t)
+		case string:
+			n = utf8.RuneCountInString(t)
+		case map[string]any:
+			n = len(t)
+		default:
+			return fmt.Errorf("path %s: %s has no length", p, jsonpath.TypeOf(t))
+		}
+	}
+
+	if (hasMin && n < min) || (hasMax && n > max) {
+		bounds := fmt.Sprintf("[%d, +inf)", min)
+		if hasMax {
+			bounds = fmt.Sprintf("[%d, %d]", min, max)
+		}
+		return fmt.Errorf("path %s: length %d outside %s", p, n, bounds)
+	}
+	return nil
+}
+
+func init() {
+	Register("expect_json_path_length_between", expectJSONPathLengthBetween,
+		required("path", TypeString),
+		oneOf("bounds", "min", TypeInt),
+		oneOf("bounds", "max", TypeInt),
+	)
+}
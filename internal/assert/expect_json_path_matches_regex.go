@@ -0,0 +1,45 @@
+package assert
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_matches_regex: checks that the value at a JSON path matches a regex.
+// Numbers and booleans are matched against their text form.
+// Kwargs:
+//
+//	path:    string (required)
+//	pattern: string (required) -> Go RE2 syntax
+//	match:   all|any (optional)
+func expectJSONPathMatchesRegex(body []byte, kw map[string]any) error {
+	pattern, ok := helpers.GetString(kw, "pattern")
+	if !ok || pattern == "" {
+		return fmt.Errorf("expect_json_path_matches_regex: missing or empty %q", "pattern")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("expect_json_path_matches_regex: invalid pattern %q: %v", pattern, err)
+	}
+	return checkJSONPath("expect_json_path_matches_regex", body, kw, func(got any) error {
+		s, err := helpers.AsString(got)
+		if err != nil {
+			return fmt.Errorf("%s is not a string", jsonpath.Format(got))
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%q does not match /%s/", s, pattern)
+		}
+		return nil
+	})
+}
+
+func init() {
+	Register("expect_json_path_matches_regex", expectJSONPathMatchesRegex,
+		required("path", TypeString),
+		required("pattern", TypeString),
+		optional("match", TypeString),
+	)
+}
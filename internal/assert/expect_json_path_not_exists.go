@@ -0,0 +1,28 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_not_exists: checks that a JSON path matches nothing.
+// Kwargs:
+//
+//	path: string (required)
+func expectJSONPathNotExists(body []byte, kw map[string]any) error {
+	p, vals, err := jsonPathMatches("expect_json_path_not_exists", body, kw)
+	if err != nil {
+		return err
+	}
+	if len(vals) > 0 {
+		return fmt.Errorf("path %s should not exist, but matched %s", p, jsonpath.Format(vals))
+	}
+	return nil
+}
+
+func init() {
+	Register("expect_json_path_not_exists", expectJSONPathNotExists,
+		required("path", TypeString),
+	)
+}
@@ -0,0 +1,111 @@
+package assert
+
+import (
+	"fmt"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// Numeric comparisons on the value at a JSON path. Numeric strings are accepted.
+//
+// expect_json_path_greater_than / expect_json_path_less_than
+//
+//	path:      string (required)
+//	value:     number (required)
+//	inclusive: bool   (optional, default false) -> >= / <=
+//	match:     all|any (optional)
+//
+// expect_json_path_between (inclusive bounds)
+//
+//	path:     string (required)
+//	min, max: number (at least one required)
+//	match:    all|any (optional)
+
+func jsonNumber(v any) (float64, error) {
+	f, err := helpers.AsFloat64(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not numeric", jsonpath.Format(v))
+	}
+	return f, nil
+}
+
+func expectJSONPathGreaterThan(body []byte, kw map[string]any) error {
+	return jsonPathCompare("expect_json_path_greater_than", body, kw, 1)
+}
+
+func expectJSONPathLessThan(body []byte, kw map[string]any) error {
+	return jsonPathCompare("expect_json_path_less_than", body, kw, -1)
+}
+
+// jsonPathCompare checks sign(got - value) == want (or 0 when inclusive).
+func jsonPathCompare(name string, body []byte, kw map[string]any, want int) error {
+	bound, ok := helpers.GetFloat64(kw, keyExpectedValue)
+	if !ok {
+		return fmt.Errorf("%s: missing or invalid %q", name, keyExpectedValue)
+	}
+	inclusive, _ := helpers.GetBool(kw, "inclusive")
+
+	op := map[int]string{1: ">", -1: "<"}[want]
+	if inclusive {
+		op += "="
+	}
+
+	return checkJSONPath(name, body, kw, func(got any) error {
+		f, err := jsonNumber(got)
+		if err != nil {
+			return err
+		}
+		if (want > 0 && f > bound) || (want < 0 && f < bound) || (inclusive && f == bound) {
+			return nil
+		}
+		return fmt.Errorf("%.6g is not %s %.6g", f, op, bound)
+	})
+}
+
+func expectJSONPathBetween(body []byte, kw map[string]any) error {
+	min, hasMin := helpers.GetFloat64(kw, keyMin)
+	max, hasMax := helpers.GetFloat64(kw, keyMax)
+	if !hasMin && !hasMax {
+		return fmt.Errorf("expect_json_path_between: at least one of %q or %q is required", keyMin, keyMax)
+	}
+
+	return checkJSONPath("expect_json_path_between", body, kw, func(got any) error {
+		f, err := jsonNumber(got)
+		if err != nil {
+			return err
+		}
+		if (hasMin && f < min) || (hasMax && f > max) {
+			lo, hi := "-inf", "+inf"
+			if hasMin {
+				lo = fmt.Sprintf("%.6g", min)
+			}
+			if hasMax {
+				hi = fmt.Sprintf("%.6g", max)
+			}
+			return fmt.Errorf("%.6g outside [%s, %s]", f, lo, hi)
+		}
+		return nil
+	})
+}
+
+func init() {
+	Register("expect_json_path_greater_than", expectJSONPathGreaterThan,
+		required("path", TypeString),
+		required("value", TypeNumber),
+		optional("inclusive", TypeBool),
+		optional("match", TypeString),
+	)
+	Register("expect_json_path_less_than", expectJSONPathLessThan,
+		required("path", TypeString),
+		required("value", TypeNumber),
+		optional("inclusive", TypeBool),
+		optional("match", TypeString),
+	)
+	Register("expect_json_path_between", expectJSONPathBetween,
+		required("path", TypeString),
+		oneOf("bounds", "min", TypeNumber),
+		oneOf("bounds", "max", TypeNumber),
+		optional("match", TypeString),
+	)
+}
@@ -0,0 +1,50 @@
+package assert
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// expect_json_path_type_is: checks the JSON type of the value at a path.
+// Kwargs:
+//
+//	path:  string (required)
+//	type:  string (required) -> string|number|integer|boolean|object|array|null
+//	match: all|any (optional)
+func expectJSONPathTypeIs(body []byte, kw map[string]any) error {
+	want, _ := helpers.GetString(kw, "type")
+	want = strings.ToLower(strings.TrimSpace(want))
+	switch want {
+	case "string", "number", "integer", "boolean", "object", "array", "null":
+	case "":
+		return fmt.Errorf("expect_json_path_type_is: missing or empty %q", "type")
+	default:
+		return fmt.Errorf("expect_json_path_type_is: unknown type %q", want)
+	}
+
+	return checkJSONPath("expect_json_path_type_is", body, kw, func(got any) error {
+		gotType := jsonpath.TypeOf(got)
+		if want == "integer" && gotType == "number" {
+			f, _ := helpers.AsFloat64(got)
+			if f == math.Trunc(f) {
+				return nil
+			}
+		}
+		if gotType != want {
+			return fmt.Errorf("type mismatch: got=%s, want=%s", gotType, want)
+		}
+		return nil
+	})
+}
+
+func init() {
+	Register("expect_json_path_type_is", expectJSONPathTypeIs,
+		required("path", TypeString),
+		required("type", TypeString),
+		optional("match", TypeString),
+	)
+}
@@ -0,0 +1,65 @@
+package assert
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonschema"
+)
+
+// expect_json_schema: validates the JSON body against a JSON Schema (draft 2020-12).
+// Kwargs (one of):
+//
+//	schema:      map    -> inline schema
+//	schema_file: string -> JSON/YAML file, relative to the suite file; may carry a
+//	                       fragment, e.g. "openapi.yaml#/components/schemas/User"
+//
+// $refs are resolved against the schema file (or the suite directory for inline schemas).
+func expectJSONSchema(body []byte, kw map[string]any) error {
+	baseDir, _ := helpers.GetString(kw, keyInjectedSuiteDir)
+
+	var (
+		schema *jsonschema.Schema
+		err    error
+	)
+	switch {
+	case kw["schema"] != nil:
+		schema, err = jsonschema.New(kw["schema"], baseDir)
+	default:
+		file, ok := helpers.GetString(kw, "schema_file")
+		if !ok || strings.TrimSpace(file) == "" {
+			return fmt.Errorf("expect_json_schema: one of %q or %q is required", "schema", "schema_file")
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(baseDir, file)
+		}
+		schema, err = jsonschema.Load(file)
+	}
+	if err != nil {
+		return fmt.Errorf("expect_json_schema: %w", err)
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("expect_json_schema: %w", err)
+	}
+
+	violations := schema.Validate(doc)
+	if len(violations) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Error()
+	}
+	return fmt.Errorf("schema validation failed (%d errors): %s", len(violations), strings.Join(msgs, "; "))
+}
+
+func init() {
+	Register("expect_json_schema", expectJSONSchema,
+		oneOf("schema", "schema", TypeMap),
+		oneOf("schema", "schema_file", TypeString),
+	)
+}
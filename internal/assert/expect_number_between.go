@@ -54,4 +54,10 @@ func numberBetween(body []byte, kwargs map[string]any) error {
 	return nil
 }
 
-func init() { Register("expect_number_to_be_between", numberBetween) }
+func init() {
+	Register("expect_number_to_be_between", numberBetween,
+		required("column", TypeString),
+		required("min", TypeNumber),
+		optional("max", TypeNumber),
+	)
+}
@@ -0,0 +1,47 @@
+package assert
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+)
+
+// Latency checks on the timings injected by the runner.
+//
+// expect_response_time_below: total time including reading the body.
+// expect_ttfb_below: time until the first response byte.
+//
+//	value: duration (required) – "250ms", "1.5s" or a number of milliseconds
+
+func expectResponseTimeBelow(_ []byte, kw map[string]any) error {
+	return durationBelow("expect_response_time_below", "response time", keyInjectedResponseTime, kw)
+}
+
+func expectTTFBBelow(_ []byte, kw map[string]any) error {
+	return durationBelow("expect_ttfb_below", "time to first byte", keyInjectedTTFB, kw)
+}
+
+func durationBelow(name, what, injected string, kw map[string]any) error {
+	actual, ok := kw[injected].(time.Duration)
+	if !ok {
+		return fmt.Errorf("%s: %q was not injected", name, injected)
+	}
+	limit, ok := helpers.GetDuration(kw, keyExpectedValue)
+	if !ok || limit <= 0 {
+		return fmt.Errorf("%s: missing or invalid %q", name, keyExpectedValue)
+	}
+	if actual >= limit {
+		return fmt.Errorf("%s %s not below %s", what, actual.Round(time.Microsecond*100), limit)
+	}
+	return nil
+}
+
+func init() {
+	Register("expect_response_time_below", expectResponseTimeBelow,
+		required("value", TypeDuration),
+	)
+	Register("expect_ttfb_below", expectTTFBBelow,
+		required("value", TypeDuration),
+	)
+}
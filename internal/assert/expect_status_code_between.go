@@ -30,5 +30,8 @@ func ExpectStatusCodeBetween(_ []byte, kw map[string]any) error {
 }
 
 func init() {
-	Register("expect_status_code_between", ExpectStatusCodeBetween)
+	Register("expect_status_code_between", ExpectStatusCodeBetween,
+		required("min", TypeInt),
+		required("max", TypeInt),
+	)
 }
@@ -22,5 +22,7 @@ func expectStatusCodeEquals(_ []byte, kw map[string]any) error {
 }
 
 func init() {
-	Register("expect_status_code_equals", expectStatusCodeEquals)
+	Register("expect_status_code_equals", expectStatusCodeEquals,
+		required("code", TypeInt),
+	)
 }
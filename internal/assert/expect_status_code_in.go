@@ -28,4 +28,8 @@ func expectStatusCodeIn(_ []byte, kw map[string]any) error {
 	return fmt.Errorf("status code %d is not in allowed set %v", code, allowed)
 }
 
-func init() { Register("expect_status_code_in", expectStatusCodeIn) }
+func init() {
+	Register("expect_status_code_in", expectStatusCodeIn,
+		required("codes", TypeAny),
+	)
+}
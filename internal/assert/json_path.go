@@ -0,0 +1,94 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// Shared plumbing for the expect_json_path_* family.
+//
+// Common kwargs:
+//
+//	path:  string (required) -> e.g. "data.items[0].id", "$.items[?(@.active)].id"
+//	match: "all" | "any" (optional, default "all") -> for paths matching several values
+const (
+	keyPath  = "path"
+	keyMatch = "match"
+)
+
+// decodeJSON decodes body keeping numbers as json.Number.
+func decodeJSON(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return v, nil
+}
+
+// jsonPathMatches decodes body and evaluates the "path" kwarg against it.
+func jsonPathMatches(name string, body []byte, kw map[string]any) (*jsonpath.Path, []any, error) {
+	raw, ok := helpers.GetString(kw, keyPath)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil, nil, fmt.Errorf("%s: missing or empty %q", name, keyPath)
+	}
+	p, err := jsonpath.Compile(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return p, p.Get(doc), nil
+}
+
+// checkJSONPath resolves the path and applies check to the matched values:
+// every match must pass, or at least one with match: any.
+func checkJSONPath(name string, body []byte, kw map[string]any, check func(v any) error) error {
+	p, vals, err := jsonPathMatches(name, body, kw)
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 {
+		return fmt.Errorf("path %s not found", p)
+	}
+
+	mode := "all"
+	if m, ok := helpers.GetString(kw, keyMatch); ok {
+		mode = strings.ToLower(strings.TrimSpace(m))
+	}
+
+	switch mode {
+	case "all":
+		for i, v := range vals {
+			if err := check(v); err != nil {
+				if len(vals) > 1 {
+					return fmt.Errorf("path %s (match %d of %d): %v", p, i+1, len(vals), err)
+				}
+				return fmt.Errorf("path %s: %v", p, err)
+			}
+		}
+		return nil
+	case "any":
+		var first error
+		for _, v := range vals {
+			err := check(v)
+			if err == nil {
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		return fmt.Errorf("path %s: none of %d matches passed (first: %v)", p, len(vals), first)
+	default:
+		return fmt.Errorf("%s: %q must be \"all\" or \"any\", got %q", name, keyMatch, mode)
+	}
+}
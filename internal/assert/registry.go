@@ -1,9 +1,54 @@
 package assert
 
+import (
+	"maps"
+	"slices"
+)
+
 type Func func(respBody []byte, kwargs map[string]any) error
 
-var registry = map[string]Func{}
+// Type is the kind of value a kwarg takes.
+type Type string
+
+const (
+	TypeString   Type = "string"
+	TypeInt      Type = "integer"
+	TypeNumber   Type = "number"
+	TypeBool     Type = "boolean"
+	TypeList     Type = "list"
+	TypeMap      Type = "map"
+	TypeDuration Type = "duration" // "250ms", "1.5s" or a number of milliseconds
+	TypeAny      Type = "any"
+)
+
+// Param describes one kwarg an assertion accepts. Params sharing a Group
+// are alternatives: at least one of them must be given.
+type Param struct {
+	Name     string
+	Type     Type
+	Required bool
+	Group    string
+}
+
+func required(name string, t Type) Param     { return Param{Name: name, Type: t, Required: true} }
+func optional(name string, t Type) Param     { return Param{Name: name, Type: t} }
+func oneOf(group, name string, t Type) Param { return Param{Name: name, Type: t, Group: group} }
 
-func Register(name string, f Func) { registry[name] = f }
+var (
+	registry = map[string]Func{}
+	params   = map[string][]Param{}
+)
+
+// Register adds an assertion together with the kwargs it accepts.
+func Register(name string, f Func, ps ...Param) {
+	registry[name] = f
+	params[name] = ps
+}
 
 func Get(name string) (Func, bool) { f, ok := registry[name]; return f, ok }
+
+// Params returns the kwargs the named assertion accepts.
+func Params(name string) ([]Param, bool) { ps, ok := params[name]; return ps, ok }
+
+// Names returns the registered assertion names, sorted.
+func Names() []string { return slices.Sorted(maps.Keys(registry)) }
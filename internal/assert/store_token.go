@@ -1,39 +1,45 @@
 package assert
 
 import (
-    "encoding/json"
-    "fmt"
+	"encoding/json"
+	"fmt"
 )
 
 const keyJSONPath = "json_path"
 
+// StoreToken saves a top-level JSON string field under the "token" key.
+//
+// Deprecated: use a request-level capture block instead, e.g.
+// `capture: { token: json:accessToken }`.
 func StoreToken(body []byte, kw map[string]any) error {
-    path, ok := kw[keyJSONPath].(string)
-    if !ok {
-        return fmt.Errorf("store_token: %s param missing or not a string", keyJSONPath)
-    }
-
-    var data map[string]any
-    if err := json.Unmarshal(body, &data); err != nil {
-        return fmt.Errorf("store_token: %w", err)
-    }
-
-    raw, ok := data[path]
-    if !ok {
-        return fmt.Errorf("store_token: field %s not present in body", path)
-    }
-
-    token, ok := raw.(string)
-    if !ok {
-        return fmt.Errorf("store_token: field %s is not a string", path)
-    }
-
-    if Ctx() != nil {
-        Ctx().Set("token", token)
-    }
-    return nil
+	path, ok := kw[keyJSONPath].(string)
+	if !ok {
+		return fmt.Errorf("store_token: %s param missing or not a string", keyJSONPath)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("store_token: %w", err)
+	}
+
+	raw, ok := data[path]
+	if !ok {
+		return fmt.Errorf("store_token: field %s not present in body", path)
+	}
+
+	token, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("store_token: field %s is not a string", path)
+	}
+
+	if Ctx() != nil {
+		Ctx().Set("token", token)
+	}
+	return nil
 }
 
 func init() {
-    Register("store_token", StoreToken)
+	Register("store_token", StoreToken,
+		required("json_path", TypeString),
+	)
 }
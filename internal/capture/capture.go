@@ -0,0 +1,107 @@
+// Package capture extracts values from HTTP responses so they can be stored
+// in the shared context and reused by later requests.
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+)
+
+// Extract resolves c against the response and returns the captured value as a string.
+func Extract(c domain.Capture, resp *http.Response, body []byte) (string, error) {
+	switch {
+	case c.Status:
+		return strconv.Itoa(resp.StatusCode), nil
+
+	case c.Header != "":
+		vals := resp.Header.Values(c.Header)
+		if len(vals) == 0 {
+			return "", fmt.Errorf("header %s not found", c.Header)
+		}
+		return vals[0], nil
+
+	case c.Cookie != "":
+		for _, ck := range resp.Cookies() {
+			if ck.Name == c.Cookie {
+				return ck.Value, nil
+			}
+		}
+		return "", fmt.Errorf("cookie %q not found", c.Cookie)
+
+	case c.JSON != "":
+		return fromJSON(body, c.JSON)
+
+	case c.Regex != "":
+		return fromRegex(body, c.Regex, c.Group)
+
+	default:
+		return "", errors.New("no capture source set (use json, header, cookie, regex or status)")
+	}
+}
+
+func fromJSON(body []byte, path string) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return "", fmt.Errorf("invalid JSON body: %v", err)
+	}
+
+	vals, err := jsonpath.Query(data, path)
+	if err != nil {
+		return "", err
+	}
+	if len(vals) == 0 {
+		return "", fmt.Errorf("path %s not present in body", path)
+	}
+	switch t := vals[0].(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case nil:
+		return "", fmt.Errorf("path %s is null", path)
+	case bool:
+		return strconv.FormatBool(t), nil
+	default:
+		// objects and arrays are stored in their JSON form
+		b, err := json.Marshal(t)
+		if err != nil {
+			return "", fmt.Errorf("path %s: %v", path, err)
+		}
+		return string(b), nil
+	}
+}
+
+func fromRegex(body []byte, expr string, group *int) (string, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex %q: %v", expr, err)
+	}
+
+	// default to the first group when the pattern has one, else the whole match
+	g := 0
+	if re.NumSubexp() > 0 {
+		g = 1
+	}
+	if group != nil {
+		g = *group
+	}
+	if g < 0 || g > re.NumSubexp() {
+		return "", fmt.Errorf("regex %q has no group %d", expr, g)
+	}
+
+	m := re.FindSubmatch(body)
+	if m == nil {
+		return "", fmt.Errorf("regex %q did not match body", expr)
+	}
+	return string(m[g]), nil
+}
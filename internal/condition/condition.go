@@ -0,0 +1,189 @@
+// Package condition evaluates the `when:` expressions of suites and requests:
+//
+//	when: "${env} == 'staging'"
+//	when: "${env} != 'prod' && ${feature_x:-off} == on"
+//	when: "${region} =~ '^eu-'"
+//
+// Operators are == != < <= > >= and =~ (regex), combined with && and ||
+// (&& binds tighter); a leading ! negates a term. A term without an operator
+// is true unless it is empty, "false" or "0". Operands are quoted strings,
+// numbers, true/false or bare words; ${var} placeholders are expanded in each
+// operand after the expression has been split, so values may contain spaces
+// or operators. Unset variables expand to "", so `when: "${flag}"` is false
+// until flag is set.
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/jsonpath"
+	"github.com/IsmailCLN/tapir/internal/templating"
+)
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+// Eval reports whether expr holds, expanding placeholders against vars.
+func Eval(expr string, vars templating.Vars) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return false, fmt.Errorf("empty condition")
+	}
+	vars = unsetEmpty{vars}
+	for _, alt := range splitOutsideQuotes(expr, "||") {
+		all := true
+		for _, term := range splitOutsideQuotes(alt, "&&") {
+			ok, err := evalTerm(strings.TrimSpace(term), vars)
+			if err != nil {
+				return false, fmt.Errorf("condition %q: %w", expr, err)
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func evalTerm(s string, vars templating.Vars) (bool, error) {
+	negate := false
+	for strings.HasPrefix(s, "!") && !strings.HasPrefix(s, "!=") {
+		negate = !negate
+		s = strings.TrimSpace(s[1:])
+	}
+	ok, err := evalCond(s, vars)
+	return ok != negate, err
+}
+
+func evalCond(s string, vars templating.Vars) (bool, error) {
+	for _, op := range operators {
+		i := indexOutsideQuotes(s, op)
+		if i < 0 {
+			continue
+		}
+		l, err := operand(strings.TrimSpace(s[:i]), vars)
+		if err != nil {
+			return false, err
+		}
+		r, err := operand(strings.TrimSpace(s[i+len(op):]), vars)
+		if err != nil {
+			return false, err
+		}
+		switch op {
+		case "==":
+			return jsonpath.Equal(l, r), nil
+		case "!=":
+			return !jsonpath.Equal(l, r), nil
+		case "=~":
+			re, err := regexp.Compile(fmt.Sprint(r))
+			if err != nil {
+				return false, fmt.Errorf("invalid regex %q: %v", r, err)
+			}
+			return re.MatchString(fmt.Sprint(l)), nil
+		}
+		cmp, ok := jsonpath.Compare(l, r)
+		if !ok {
+			return false, fmt.Errorf("cannot compare %v %s %v", l, op, r)
+		}
+		switch op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+
+	v, err := operand(s, vars)
+	if err != nil {
+		return false, err
+	}
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	default:
+		return v != "", nil
+	}
+}
+
+// operand decodes one side of a comparison.
+func operand(s string, vars templating.Vars) (any, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("missing operand")
+	case len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]:
+		return templating.Expand(unquote(s), vars), nil
+	}
+	s = templating.Expand(s, vars)
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// unsetEmpty resolves names vars does not know to "", where
+// templating.Expand would keep the placeholder text.
+type unsetEmpty struct{ vars templating.Vars }
+
+func (u unsetEmpty) Lookup(name string) (string, bool) {
+	if u.vars != nil {
+		if v, ok := u.vars.Lookup(name); ok {
+			return v, true
+		}
+	}
+	return "", true
+}
+
+func unquote(s string) string {
+	q := s[0]
+	s = s[1 : len(s)-1]
+	return strings.ReplaceAll(s, `\`+string(q), string(q))
+}
+
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	for {
+		i := indexOutsideQuotes(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+func indexOutsideQuotes(s, sub string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
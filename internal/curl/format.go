@@ -0,0 +1,74 @@
+package curl
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Format renders a request as a copy-pasteable curl command, one option per
+// line. The body is sent verbatim with --data-raw.
+func Format(method, rawURL string, header http.Header, body []byte) string {
+	return FormatEnv(method, rawURL, header, body, nil)
+}
+
+// FormatEnv is Format with the values in env, mapped to the environment
+// variable holding them, written as "${NAME}" so the shell fills them in.
+func FormatEnv(method, rawURL string, header http.Header, body []byte, env map[string]string) string {
+	values := slices.SortedFunc(maps.Keys(env), func(a, b string) int { return len(b) - len(a) })
+	quote := func(s string) string { return quoteEnv(s, values, env) }
+
+	var b strings.Builder
+	b.WriteString("curl")
+	switch method {
+	case "", http.MethodGet:
+		if len(body) > 0 {
+			b.WriteString(" -X GET")
+		}
+	case http.MethodHead:
+		b.WriteString(" --head")
+	default:
+		b.WriteString(" -X " + method)
+	}
+	b.WriteString(" " + quote(rawURL))
+
+	for _, k := range slices.Sorted(maps.Keys(header)) {
+		for _, v := range header[k] {
+			b.WriteString(" \\\n  -H " + quote(k+": "+v))
+		}
+	}
+	if len(body) > 0 {
+		b.WriteString(" \\\n  --data-raw " + quote(string(body)))
+	}
+	return b.String()
+}
+
+// quoteEnv quotes s, writing every occurrence of values (longest first) as a
+// double-quoted variable reference.
+func quoteEnv(s string, values []string, env map[string]string) string {
+	for _, v := range values {
+		if i := strings.Index(s, v); i >= 0 {
+			var b strings.Builder
+			if i > 0 {
+				b.WriteString(quoteEnv(s[:i], values, env))
+			}
+			b.WriteString(`"${` + env[v] + `}"`)
+			if rest := s[i+len(v):]; rest != "" {
+				b.WriteString(quoteEnv(rest, values, env))
+			}
+			return b.String()
+		}
+	}
+	return Quote(s)
+}
+
+// Quote wraps s in single quotes for POSIX shells.
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,422 @@
+// Package curl converts between curl command lines and tapir requests.
+package curl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+)
+
+// noArg are options that take no value and do not change the request.
+var noArg = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "-L": true, "--location": true,
+	"-k": true, "--insecure": true, "--compressed": true, "-v": true, "--verbose": true,
+	"-i": true, "--include": true, "-f": true, "--fail": true, "-g": true, "--globoff": true,
+	"-N": true, "--no-buffer": true, "-#": true, "--progress-bar": true, "--http1.1": true,
+	"--http2": true, "-G": true, "--get": true, "-I": true, "--head": true,
+}
+
+// ignoredArg are options that take a value but do not change the request.
+var ignoredArg = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-w": true, "--write-out": true, "--retry": true, "-x": true, "--proxy": true,
+	"--cacert": true, "-E": true, "--cert": true, "--key": true, "-c": true, "--cookie-jar": true,
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Split tokenizes a shell command line. It understands single and double
+// quotes, backslash escapes and backslash-newline continuations.
+func Split(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+			if c != '\n' {
+				cur.WriteRune(c)
+				inWord = true
+			}
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case quote == '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// ParseAll parses every curl command in text. Commands may span several lines
+// using backslash continuations; each one starts with the word "curl".
+func ParseAll(text string) ([]domain.TestRequest, error) {
+	// Drop a leading "$ " prompt, which is common in pasted snippets.
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		lines = append(lines, strings.TrimPrefix(strings.TrimLeft(l, " \t"), "$ "))
+	}
+	args, err := Split(strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds [][]string
+	for _, a := range args {
+		if a == "curl" {
+			cmds = append(cmds, nil)
+			continue
+		}
+		if len(cmds) == 0 {
+			return nil, fmt.Errorf("expected a curl command, got %q", a)
+		}
+		cmds[len(cmds)-1] = append(cmds[len(cmds)-1], a)
+	}
+	if len(cmds) == 0 {
+		return nil, errors.New("no curl command found")
+	}
+
+	reqs := make([]domain.TestRequest, 0, len(cmds))
+	names := map[string]int{}
+	for i, c := range cmds {
+		r, err := Parse(c)
+		if err != nil {
+			return nil, fmt.Errorf("command %d: %w", i+1, err)
+		}
+		names[r.Name]++
+		if n := names[r.Name]; n > 1 {
+			r.Name = fmt.Sprintf("%s_%d", r.Name, n)
+		}
+		reqs = append(reqs, r)
+	}
+	return reqs, nil
+}
+
+// Parse converts the arguments of a single curl command (without the leading
+// "curl") into a request.
+func Parse(args []string) (domain.TestRequest, error) {
+	var (
+		method, rawURL string
+		headers        = map[string]string{}
+		data           []string
+		dataFile       string
+		auth           *domain.Auth
+		jsonData       bool
+		parts          []domain.MultipartPart
+		get, head      bool
+	)
+
+	for i := 0; i < len(args); i++ {
+		opt := args[i]
+		if !strings.HasPrefix(opt, "-") || opt == "-" {
+			if rawURL != "" {
+				return domain.TestRequest{}, fmt.Errorf("more than one URL: %q and %q", rawURL, opt)
+			}
+			rawURL = opt
+			continue
+		}
+
+		// Expand bundled short flags such as -sSL.
+		if !strings.HasPrefix(opt, "--") && len(opt) > 2 && bundled(opt) {
+			for _, c := range opt[2:] {
+				args = append(args[:i+1], append([]string{"-" + string(c)}, args[i+1:]...)...)
+			}
+			opt = opt[:2]
+		}
+
+		// Short options accept an attached value, as in -XPOST.
+		var val string
+		hasVal := false
+		if !strings.HasPrefix(opt, "--") && len(opt) > 2 {
+			opt, val, hasVal = opt[:2], opt[2:], true
+		}
+		value := func() (string, error) {
+			if hasVal {
+				return val, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s needs a value", opt)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch opt {
+		case "-G", "--get":
+			get = true
+		case "-I", "--head":
+			head = true
+		}
+		if noArg[opt] {
+			continue
+		}
+
+		v, err := value()
+		if err != nil {
+			return domain.TestRequest{}, err
+		}
+		switch opt {
+		case "-X", "--request":
+			method = strings.ToUpper(v)
+		case "--url":
+			rawURL = v
+		case "-H", "--header":
+			k, hv, ok := strings.Cut(v, ":")
+			if !ok {
+				return domain.TestRequest{}, fmt.Errorf("malformed header %q", v)
+			}
+			headers[strings.TrimSpace(k)] = strings.TrimSpace(hv)
+		case "-A", "--user-agent":
+			headers["User-Agent"] = v
+		case "-e", "--referer":
+			headers["Referer"] = v
+		case "-b", "--cookie":
+			// Without "=" the value names a cookie jar file, which we can't use.
+			if strings.Contains(v, "=") {
+				headers["Cookie"] = v
+			}
+		case "-u", "--user":
+			user, pass, _ := strings.Cut(v, ":")
+			auth = &domain.Auth{Type: domain.AuthBasic, Username: user, Password: pass}
+		case "-d", "--data", "--data-ascii", "--data-binary":
+			if strings.HasPrefix(v, "@") {
+				dataFile = v[1:]
+				continue
+			}
+			data = append(data, v)
+		case "--data-raw":
+			data = append(data, v)
+		case "--json":
+			jsonData = true
+			data = append(data, v)
+		case "--data-urlencode":
+			data = append(data, urlencode(v))
+		case "-F", "--form", "--form-string":
+			p, err := formPart(v, opt == "--form-string")
+			if err != nil {
+				return domain.TestRequest{}, err
+			}
+			parts = append(parts, p)
+		default:
+			if !ignoredArg[opt] {
+				return domain.TestRequest{}, fmt.Errorf("unsupported option %s", opt)
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return domain.TestRequest{}, errors.New("no URL given")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.TestRequest{}, err
+	}
+	if u.Scheme == "" {
+		// curl defaults to http:// when the scheme is missing.
+		if u, err = url.Parse("http://" + rawURL); err != nil {
+			return domain.TestRequest{}, err
+		}
+	}
+	if dataFile != "" && (len(data) > 0 || len(parts) > 0) || len(data) > 0 && len(parts) > 0 {
+		return domain.TestRequest{}, errors.New("mixing -d, -d @file and -F is not supported")
+	}
+
+	req := domain.HTTPRequest{Method: method, Auth: auth}
+	payload := strings.Join(data, "&")
+	switch {
+	case get && len(data) > 0:
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += payload
+	case head:
+	case len(parts) > 0:
+		req.Multipart = parts
+	case dataFile != "":
+		req.BodyFile = dataFile
+		setDefault(headers, "Content-Type", "application/x-www-form-urlencoded")
+	case len(data) > 0:
+		if jsonData {
+			setDefault(headers, "Content-Type", "application/json")
+			setDefault(headers, "Accept", "application/json")
+		}
+		req.Body, req.Form = body(payload, headers)
+	}
+
+	switch {
+	case req.Method != "":
+	case head:
+		req.Method = "HEAD"
+	case !get && (len(data) > 0 || dataFile != "" || len(parts) > 0):
+		req.Method = "POST"
+	default:
+		req.Method = "GET"
+	}
+	req.URL = u.String()
+	if len(headers) > 0 {
+		req.Headers = headers
+	}
+
+	slug := strings.Trim(nonWord.ReplaceAllString(path.Clean("/"+u.Path), "_"), "_")
+	if slug == "" {
+		slug = "root"
+	}
+	return domain.TestRequest{
+		Name: strings.ToLower(req.Method) + "_" + slug,
+		Req:  req,
+	}, nil
+}
+
+// body picks the most natural representation of a -d payload: a structured
+// body for JSON, a form for url-encoded pairs and a raw string otherwise.
+// Content-Type headers that tapir would set by itself are dropped.
+func body(payload string, headers map[string]string) (any, map[string]any) {
+	ctKey := headerKey(headers, "Content-Type")
+	ct, hasCT := headers[ctKey]
+	mt, _, _ := mime.ParseMediaType(ct)
+
+	switch {
+	case !hasCT || mt == "application/x-www-form-urlencoded":
+		if form, ok := parseForm(payload); ok {
+			delete(headers, ctKey)
+			return nil, form
+		}
+		// curl labels every -d payload as a form unless told otherwise.
+		setDefault(headers, "Content-Type", "application/x-www-form-urlencoded")
+		return payload, nil
+	case mt == "application/json":
+		var v any
+		if err := json.Unmarshal([]byte(payload), &v); err == nil {
+			if _, isString := v.(string); !isString {
+				delete(headers, ctKey)
+				return v, nil
+			}
+		}
+	}
+	return payload, nil
+}
+
+// parseForm accepts payloads made of unique, well-formed key=value pairs.
+func parseForm(payload string) (map[string]any, bool) {
+	q, err := url.ParseQuery(payload)
+	if err != nil || len(q) == 0 {
+		return nil, false
+	}
+	form := make(map[string]any, len(q))
+	for k, vs := range q {
+		if len(vs) != 1 || k == "" {
+			return nil, false
+		}
+		form[k] = vs[0]
+	}
+	if strings.Count(payload, "&")+1 != len(form) || !strings.Contains(payload, "=") {
+		return nil, false
+	}
+	return form, true
+}
+
+// urlencode mirrors curl's --data-urlencode forms: "content", "=content" and
+// "name=content". The @file variants are not supported.
+func urlencode(v string) string {
+	name, content, ok := strings.Cut(v, "=")
+	if !ok {
+		return url.QueryEscape(v)
+	}
+	if name == "" {
+		return url.QueryEscape(content)
+	}
+	return name + "=" + url.QueryEscape(content)
+}
+
+// formPart parses a -F value: name=value, name=@file or name=<file, each
+// optionally followed by ;type= and ;filename= attributes.
+func formPart(v string, literal bool) (domain.MultipartPart, error) {
+	name, rest, ok := strings.Cut(v, "=")
+	if !ok || name == "" {
+		return domain.MultipartPart{}, fmt.Errorf("malformed form field %q", v)
+	}
+	p := domain.MultipartPart{Name: name}
+	if literal || (!strings.HasPrefix(rest, "@") && !strings.HasPrefix(rest, "<")) {
+		p.Value = rest
+		return p, nil
+	}
+
+	attrs := strings.Split(rest[1:], ";")
+	p.File = attrs[0]
+	for _, a := range attrs[1:] {
+		k, av, _ := strings.Cut(a, "=")
+		switch strings.TrimSpace(k) {
+		case "type":
+			p.ContentType = av
+		case "filename":
+			p.Filename = strings.Trim(av, `"`)
+		}
+	}
+	return p, nil
+}
+
+// bundled reports whether opt is a group of value-less short flags like -sSL.
+func bundled(opt string) bool {
+	for _, c := range opt[1:] {
+		if !noArg["-"+string(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+func setDefault(h map[string]string, name, value string) {
+	if _, ok := h[headerKey(h, name)]; !ok {
+		h[name] = value
+	}
+}
+
+func headerKey(h map[string]string, name string) string {
+	for k := range h {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
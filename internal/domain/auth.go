@@ -0,0 +1,39 @@
+package domain
+
+// Auth configures how a request authenticates. On a suite it applies to every
+// request; a request's own auth replaces it, and type "none" turns it off.
+//
+//	auth: { type: basic, username: bob, password: "${env:BOB_PASSWORD}" }
+//	auth: { type: bearer, token: "${token}" }
+//	auth: { type: api_key, name: X-Api-Key, value: "${env:API_KEY}" }
+//	auth: { type: oauth2_client_credentials, token_url: https://idp/token,
+//	        client_id: tapir, client_secret: "${env:CLIENT_SECRET}", scopes: [read] }
+type Auth struct {
+	Type string `yaml:"type"` // basic | bearer | api_key | oauth2_client_credentials | none
+
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+
+	Token string `yaml:"token,omitempty"`
+
+	// API key: sent as header Name, or as query parameter Name when In is "query".
+	Name  string `yaml:"name,omitempty"`
+	Value string `yaml:"value,omitempty"`
+	In    string `yaml:"in,omitempty"` // header (default) | query
+
+	// OAuth2 client credentials grant. The client authenticates with HTTP
+	// basic auth; the token is cached for the run until it expires.
+	TokenURL     string   `yaml:"token_url,omitempty"`
+	ClientID     string   `yaml:"client_id,omitempty"`
+	ClientSecret string   `yaml:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+}
+
+// Auth types.
+const (
+	AuthNone              = "none"
+	AuthBasic             = "basic"
+	AuthBearer            = "bearer"
+	AuthAPIKey            = "api_key"
+	AuthOAuth2Credentials = "oauth2_client_credentials"
+)
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Capture describes where to pull a value out of a response. Exactly one
+// source (JSON, Header, Cookie, Regex or Status) should be set.
+//
+// Besides the mapping form, a scalar shorthand is accepted:
+//
+//	capture:
+//	  user_id: json:data.id
+//	  etag:    header:ETag
+//	  session: cookie:SESSIONID
+//	  order:   regex:order-(\d+)
+//	  code:    status
+type Capture struct {
+	JSON   string `yaml:"json,omitempty"`
+	Header string `yaml:"header,omitempty"`
+	Cookie string `yaml:"cookie,omitempty"`
+	Regex  string `yaml:"regex,omitempty"`
+	Group  *int   `yaml:"group,omitempty"`
+	Status bool   `yaml:"status,omitempty"`
+}
+
+func (c *Capture) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.ScalarNode {
+		type plain Capture
+		return n.Decode((*plain)(c))
+	}
+
+	if strings.TrimSpace(n.Value) == "status" {
+		c.Status = true
+		return nil
+	}
+	src, expr, ok := strings.Cut(n.Value, ":")
+	if !ok || expr == "" {
+		return fmt.Errorf("line %d: capture %q must look like <source>:<expr>", n.Line, n.Value)
+	}
+	switch strings.TrimSpace(src) {
+	case "json":
+		c.JSON = expr
+	case "header":
+		c.Header = expr
+	case "cookie":
+		c.Cookie = expr
+	case "regex":
+		c.Regex = expr
+	default:
+		return fmt.Errorf("line %d: unknown capture source %q (use json|header|cookie|regex|status)", n.Line, src)
+	}
+	return nil
+}
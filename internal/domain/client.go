@@ -0,0 +1,55 @@
+package domain
+
+// ClientOptions tune the HTTP client. They can be given on the command line,
+// on a suite and on a request; each level overrides the one before it, key by
+// key. Relative file paths are resolved against the suite's directory.
+//
+//	timeout: 5s
+//	follow_redirects: true
+//	max_redirects: 3
+//	proxy: http://proxy.internal:3128
+//	ca_file: certs/internal-ca.pem
+//	cert_file: certs/client.pem
+//	key_file: certs/client-key.pem
+//	http2: false
+type ClientOptions struct {
+	Timeout         Duration `yaml:"timeout,omitempty"`
+	FollowRedirects *bool    `yaml:"follow_redirects,omitempty"`
+	MaxRedirects    int      `yaml:"max_redirects,omitempty"` // 0: the default of 10
+
+	Proxy              string `yaml:"proxy,omitempty"` // default: HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	InsecureSkipVerify *bool  `yaml:"insecure_skip_verify,omitempty"`
+	CAFile             string `yaml:"ca_file,omitempty"`   // PEM bundle trusted in addition to the system roots
+	CertFile           string `yaml:"cert_file,omitempty"` // client certificate for mTLS
+	KeyFile            string `yaml:"key_file,omitempty"`  // its private key; may be omitted if CertFile holds both
+	HTTP2              *bool  `yaml:"http2,omitempty"`     // default true
+}
+
+// Or returns o with every unset option taken from def.
+func (o ClientOptions) Or(def ClientOptions) ClientOptions {
+	if o.Timeout == 0 {
+		o.Timeout = def.Timeout
+	}
+	if o.FollowRedirects == nil {
+		o.FollowRedirects = def.FollowRedirects
+	}
+	if o.MaxRedirects == 0 {
+		o.MaxRedirects = def.MaxRedirects
+	}
+	if o.Proxy == "" {
+		o.Proxy = def.Proxy
+	}
+	if o.InsecureSkipVerify == nil {
+		o.InsecureSkipVerify = def.InsecureSkipVerify
+	}
+	if o.CAFile == "" {
+		o.CAFile = def.CAFile
+	}
+	if o.CertFile == "" {
+		o.CertFile, o.KeyFile = def.CertFile, def.KeyFile
+	}
+	if o.HTTP2 == nil {
+		o.HTTP2 = def.HTTP2
+	}
+	return o
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration written in YAML as "1.5s", "250ms" or a plain
+// number of milliseconds.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return err
+	}
+	dur, err := helpers.AsDuration(v)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid duration %q", n.Line, n.Value)
+	}
+	*d = Duration(dur)
+	return nil
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Retry re-sends a request until its conditions hold or the attempts run out,
+// for endpoints that only eventually return what the test expects:
+//
+//	retry:
+//	  attempts: 10
+//	  interval: 500ms
+//	  backoff: exponential   # interval, 2×interval, 4×interval, …
+//	  max_interval: 5s
+//	  jitter: 0.2            # ±20 % on every wait
+//	  until:                 # default: the request's own expect list
+//	    - expectation_type: expect_status_code_equals
+//	      kwargs: { code: 200 }
+type Retry struct {
+	Attempts    int           `yaml:"attempts"`               // including the first; default 3
+	Interval    Duration      `yaml:"interval,omitempty"`     // wait before the second attempt; default 1s
+	Backoff     string        `yaml:"backoff,omitempty"`      // fixed (default) | exponential
+	MaxInterval Duration      `yaml:"max_interval,omitempty"` // caps exponential waits
+	Jitter      float64       `yaml:"jitter,omitempty"`       // fraction of each wait added or removed at random
+	Until       []Expectation `yaml:"until,omitempty"`
+}
+
+// Backoff strategies.
+const (
+	BackoffFixed       = "fixed"
+	BackoffExponential = "exponential"
+)
+
+func (r *Retry) UnmarshalYAML(n *yaml.Node) error {
+	type plain Retry
+	if err := n.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	switch r.Backoff {
+	case "", BackoffFixed, BackoffExponential:
+		return nil
+	}
+	line := n.Line
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == "backoff" {
+			line = n.Content[i+1].Line
+		}
+	}
+	return fmt.Errorf("line %d: unknown backoff %q (use %s or %s)", line, r.Backoff, BackoffFixed, BackoffExponential)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Skip turns a suite or request off. It is written as a boolean or as the
+// reason for skipping:
+//
+//	skip: true
+//	skip: "flaky until #123 is fixed"
+type Skip struct {
+	Skipped bool
+	Reason  string
+}
+
+func (s *Skip) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: skip must be true, false or a reason", n.Line)
+	}
+	var b bool
+	if n.Tag == "!!bool" {
+		if err := n.Decode(&b); err != nil {
+			return err
+		}
+		*s = Skip{Skipped: b}
+		return nil
+	}
+	*s = Skip{Skipped: n.Value != "", Reason: n.Value}
+	return nil
+}
+
+func (s Skip) MarshalYAML() (any, error) {
+	if s.Reason != "" {
+		return s.Reason, nil
+	}
+	return s.Skipped, nil
+}
+
+// IsZero lets omitempty drop a Skip that is not set.
+func (s Skip) IsZero() bool { return !s.Skipped }
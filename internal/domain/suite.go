@@ -1,25 +1,75 @@
 package domain
 
 type TestSuite struct {
-	Name     string        `yaml:"suite_name"`
+	Name string `yaml:"suite_name"`
+
+	// Defaults for every request of the suite. A request may override each.
+	BaseURL       string            `yaml:"base_url,omitempty"` // relative request URLs are appended to it
+	Headers       map[string]string `yaml:"headers,omitempty"`
+	Auth          *Auth             `yaml:"auth,omitempty"`
+	ClientOptions `yaml:",inline"`
+
+	// Selection, see TestRequest.
+	Skip Skip   `yaml:"skip,omitempty"`
+	Only bool   `yaml:"only,omitempty"`
+	When string `yaml:"when,omitempty"`
+
 	Requests []TestRequest `yaml:"requests"`
+
+	// Path is the file the suite was loaded from. Relative file references
+	// (body_file, multipart files, …) are resolved against its directory.
+	Path string `yaml:"-"`
 }
 
 type TestRequest struct {
-	Name       string        `yaml:"name"`
-	Req        HTTPRequest   `yaml:"request"`
-	Expect     []Expectation `yaml:"expect"`
-	DependsOn  []string      `yaml:"depends_on,omitempty"`
+	Name      string             `yaml:"name"`
+	Req       HTTPRequest        `yaml:"request"`
+	Expect    []Expectation      `yaml:"expect,omitempty"`
+	Capture   map[string]Capture `yaml:"capture,omitempty"`
+	DependsOn []string           `yaml:"depends_on,omitempty"`
+	Retry     *Retry             `yaml:"retry,omitempty"`
+
+	// Skip reports the request as skipped without sending it. When any
+	// suite or request of a run sets Only, everything else is skipped. When
+	// is a condition (see package condition) evaluated right before the
+	// request would run; the request is skipped unless it holds.
+	Skip Skip   `yaml:"skip,omitempty"`
+	Only bool   `yaml:"only,omitempty"`
+	When string `yaml:"when,omitempty"`
+
+	// RunAlways runs the request even when a request it depends on failed
+	// or was skipped, e.g. for cleanup. Otherwise it is skipped as blocked.
+	RunAlways bool `yaml:"run_always,omitempty"`
 }
 
+// HTTPRequest describes what to send. At most one of Body, Form, Multipart
+// and BodyFile may be set.
 type HTTPRequest struct {
 	Method  string            `yaml:"method"`
 	URL     string            `yaml:"url"`
-	Body    any               `yaml:"body,omitempty"`
+	Body    any               `yaml:"body,omitempty"` // string is sent as-is, maps/lists as JSON
 	Headers map[string]string `yaml:"headers,omitempty"`
+
+	Form      map[string]any  `yaml:"form,omitempty"` // values may be scalars or lists
+	Multipart []MultipartPart `yaml:"multipart,omitempty"`
+	BodyFile  string          `yaml:"body_file,omitempty"`
+
+	// Overrides of the suite defaults.
+	Auth          *Auth `yaml:"auth,omitempty"`
+	ClientOptions `yaml:",inline"`
+}
+
+// MultipartPart is one field of a multipart/form-data body: either an inline
+// Value or a File read from disk.
+type MultipartPart struct {
+	Name        string `yaml:"name"`
+	Value       string `yaml:"value,omitempty"`
+	File        string `yaml:"file,omitempty"`
+	Filename    string `yaml:"filename,omitempty"`     // defaults to the base name of File
+	ContentType string `yaml:"content_type,omitempty"` // defaults to a guess from the extension
 }
 
 type Expectation struct {
-	Type   string                 `yaml:"expectation_type"`
+	Type   string         `yaml:"expectation_type"`
 	Kwargs map[string]any `yaml:"kwargs"`
 }
@@ -0,0 +1,222 @@
+// Package har turns HTTP Archive (HAR 1.2) recordings into regression suites.
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+)
+
+type File struct {
+	Log struct {
+		Entries []Entry `json:"entries"`
+	} `json:"log"`
+}
+
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method   string    `json:"method"`
+	URL      string    `json:"url"`
+	Headers  []NV      `json:"headers"`
+	PostData *PostData `json:"postData"`
+}
+
+type Response struct {
+	Status  int `json:"status"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Params   []NV   `json:"params"`
+}
+
+type NV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Options filters and shapes the import. Empty filters match everything.
+type Options struct {
+	Hosts        []string // exact host names (port ignored)
+	Methods      []string
+	ContentTypes []string // substrings of the response media type, e.g. "json"
+	Snapshot     bool     // pin the recorded response body with expect_body_equals
+}
+
+// skipHeaders are set by the client itself or tie the request to the
+// recorded browser session. Credentials are dropped so they are not written
+// to the suite; add an auth block instead.
+var skipHeaders = map[string]bool{
+	"host": true, "content-length": true, "connection": true, "accept-encoding": true,
+	"cookie": true, "authorization": true, "keep-alive": true, "te": true, "upgrade": true, "transfer-encoding": true,
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Load reads a .har file.
+func Load(p string) (*File, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", p, err)
+	}
+	return &f, nil
+}
+
+// Convert returns one suite per host, with requests in recording order.
+func Convert(f *File, opts Options) []domain.TestSuite {
+	var order []string
+	byHost := map[string][]domain.TestRequest{}
+	names := map[string]map[string]int{}
+
+	for _, e := range f.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || e.Response.Status == 0 || !opts.keep(e, u) {
+			continue
+		}
+		host := u.Hostname()
+		if _, ok := byHost[host]; !ok {
+			order = append(order, host)
+			names[host] = map[string]int{}
+		}
+
+		r := convertEntry(e, u, opts)
+		names[host][r.Name]++
+		if n := names[host][r.Name]; n > 1 {
+			r.Name = fmt.Sprintf("%s_%d", r.Name, n)
+		}
+		byHost[host] = append(byHost[host], r)
+	}
+
+	suites := make([]domain.TestSuite, 0, len(order))
+	for _, h := range order {
+		suites = append(suites, domain.TestSuite{Name: h, Requests: byHost[h]})
+	}
+	return suites
+}
+
+func (o Options) keep(e Entry, u *url.URL) bool {
+	if len(o.Hosts) > 0 && !containsFold(o.Hosts, u.Hostname()) {
+		return false
+	}
+	if len(o.Methods) > 0 && !containsFold(o.Methods, e.Request.Method) {
+		return false
+	}
+	if len(o.ContentTypes) > 0 {
+		ct := strings.ToLower(e.Response.Content.MimeType)
+		for _, want := range o.ContentTypes {
+			if strings.Contains(ct, strings.ToLower(want)) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func convertEntry(e Entry, u *url.URL, opts Options) domain.TestRequest {
+	slug := strings.Trim(nonWord.ReplaceAllString(path.Clean("/"+u.Path), "_"), "_")
+	if slug == "" {
+		slug = "root"
+	}
+	r := domain.TestRequest{
+		Name: strings.ToLower(e.Request.Method) + "_" + slug,
+		Req: domain.HTTPRequest{
+			Method: strings.ToUpper(e.Request.Method),
+			URL:    e.Request.URL,
+		},
+	}
+
+	for _, h := range e.Request.Headers {
+		name := strings.ToLower(h.Name)
+		if strings.HasPrefix(name, ":") || skipHeaders[name] {
+			continue
+		}
+		if r.Req.Headers == nil {
+			r.Req.Headers = map[string]string{}
+		}
+		r.Req.Headers[h.Name] = h.Value
+	}
+
+	if pd := e.Request.PostData; pd != nil {
+		mt, _, _ := mime.ParseMediaType(pd.MimeType)
+		switch {
+		case mt == "application/x-www-form-urlencoded" && len(pd.Params) > 0:
+			r.Req.Form = map[string]any{}
+			for _, p := range pd.Params {
+				r.Req.Form[p.Name] = p.Value
+			}
+			delete(r.Req.Headers, headerKey(r.Req.Headers, "Content-Type"))
+		case isJSON(mt):
+			var v any
+			if err := json.Unmarshal([]byte(pd.Text), &v); err == nil {
+				r.Req.Body = v
+				delete(r.Req.Headers, headerKey(r.Req.Headers, "Content-Type"))
+				break
+			}
+			r.Req.Body = pd.Text
+		case pd.Text != "":
+			r.Req.Body = pd.Text
+		}
+	}
+
+	r.Expect = append(r.Expect, domain.Expectation{
+		Type:   "expect_status_code_equals",
+		Kwargs: map[string]any{"code": e.Response.Status},
+	})
+	if mt, _, err := mime.ParseMediaType(e.Response.Content.MimeType); err == nil && mt != "" {
+		r.Expect = append(r.Expect, domain.Expectation{
+			Type:   "expect_content_type_matches",
+			Kwargs: map[string]any{"value": mt, "ignore_params": true},
+		})
+	}
+	if opts.Snapshot && e.Response.Content.Text != "" && e.Response.Content.Encoding == "" {
+		r.Expect = append(r.Expect, domain.Expectation{
+			Type:   "expect_body_equals",
+			Kwargs: map[string]any{"value": e.Response.Content.Text},
+		})
+	}
+	return r
+}
+
+func isJSON(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+func headerKey(h map[string]string, name string) string {
+	for k := range h {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(strings.TrimSpace(e), s) {
+			return true
+		}
+	}
+	return false
+}
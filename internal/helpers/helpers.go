@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -40,6 +41,8 @@ func AsInt(v any) (int, error) {
 	switch t := v.(type) {
 	case int:
 		return t, nil
+	case json.Number:
+		return AsInt(t.String())
 	case int64:
 		return int(t), nil
 	case int32:
@@ -73,6 +76,8 @@ func AsFloat64(v any) (float64, error) {
 	switch t := v.(type) {
 	case float64:
 		return t, nil
+	case json.Number:
+		return AsFloat64(t.String())
 	case float32:
 		return float64(t), nil
 	case int:
@@ -2,28 +2,117 @@ package httpclient
 
 import (
 	"context"
-	"net"
+	"crypto/tls"
+	"fmt"
 	"net/http"
+	"net/http/httptrace"
+	"sync"
 	"time"
 )
 
-var transport = &http.Transport{
-	MaxIdleConns:        100,
-	MaxIdleConnsPerHost: 10,
-	IdleConnTimeout:     90 * time.Second,
-	DialContext: (&net.Dialer{
-		Timeout:   5 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}).DialContext,
-	TLSHandshakeTimeout:   5 * time.Second,
-	ExpectContinueTimeout: 1 * time.Second,
+// DefaultTimeout bounds a request, including reading its body, unless the
+// request sets its own timeout.
+const DefaultTimeout = 15 * time.Second
+
+// DefaultMaxRedirects is the redirect limit of net/http's default policy.
+const DefaultMaxRedirects = 10
+
+// Options are per-request client settings.
+type Options struct {
+	// NoRedirects returns 3xx responses as they are instead of following them.
+	NoRedirects bool
+	// MaxRedirects is the number of redirects followed before giving up.
+	// 0 means DefaultMaxRedirects.
+	MaxRedirects int
+
+	Transport
+}
+
+type optionsKey struct{}
+
+// checkRedirect applies the redirect policy of the Options stored in the
+// request's context by Do.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	o, _ := req.Context().Value(optionsKey{}).(Options)
+	if o.NoRedirects {
+		return http.ErrUseLastResponse
+	}
+	limit := o.MaxRedirects
+	if limit <= 0 {
+		limit = DefaultMaxRedirects
+	}
+	// via holds the requests made so far, the first of which was no redirect.
+	if len(via) > limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	return nil
+}
+
+// Timing breaks a request down into phases. Phases that did not happen, such
+// as DNS and connect on a reused connection, stay zero.
+type Timing struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TTFB is the time from starting the request to the first response byte.
+	TTFB time.Duration
+	// Total runs until Done is called, normally after the body was read.
+	Total time.Duration
+
+	mu                         sync.Mutex
+	start, dns, connect, tlsAt time.Time
+}
+
+// Done records the total time of the request.
+func (t *Timing) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Total = time.Since(t.start)
 }
 
-var client = &http.Client{
-	Transport: transport,
-	Timeout:   15 * time.Second,
+func (t *Timing) trace() *httptrace.ClientTrace {
+	lock := func(f func()) { t.mu.Lock(); f(); t.mu.Unlock() }
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { lock(func() { t.dns = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { lock(func() { t.DNS = time.Since(t.dns) }) },
+		ConnectStart: func(string, string) {
+			lock(func() {
+				if t.connect.IsZero() {
+					t.connect = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			lock(func() {
+				if err == nil && t.Connect == 0 {
+					t.Connect = time.Since(t.connect)
+				}
+			})
+		},
+		TLSHandshakeStart: func() { lock(func() { t.tlsAt = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			lock(func() { t.TLS = time.Since(t.tlsAt) })
+		},
+		GotFirstResponseByte: func() { lock(func() { t.TTFB = time.Since(t.start) }) },
+	}
 }
 
-func Do(ctx context.Context, req *http.Request) (*http.Response, error) {
-	return client.Do(req.WithContext(ctx))
+// Do sends req and traces its phases. The returned Timing is never nil; call
+// its Done method once the response body has been consumed. ctx should carry
+// the request's deadline (DefaultTimeout unless configured otherwise) and
+// stay alive until the body has been read.
+func Do(ctx context.Context, req *http.Request, opts Options) (*http.Response, *Timing, error) {
+	t := &Timing{start: time.Now()}
+	ctx = context.WithValue(ctx, optionsKey{}, opts)
+	ctx = httptrace.WithClientTrace(ctx, t.trace())
+	client, err := clientFor(opts.Transport)
+	if err != nil {
+		t.Done()
+		return nil, t, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Done()
+	}
+	return resp, t, err
 }
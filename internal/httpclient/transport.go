@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Transport holds the connection-level settings of a request. Requests with
+// equal settings share one client and its connection pool.
+type Transport struct {
+	// Proxy is the proxy URL. Empty means HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY from the environment.
+	Proxy string
+
+	InsecureSkipVerify bool
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// CertFile and KeyFile are the client certificate and key for mTLS.
+	// KeyFile may be empty when CertFile holds both.
+	CertFile string
+	KeyFile  string
+
+	DisableHTTP2 bool
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[Transport]*http.Client{}
+)
+
+// clientFor returns the client for cfg, creating it on first use.
+func clientFor(cfg Transport) (*http.Client, error) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if c, ok := clients[cfg]; ok {
+		return c, nil
+	}
+	t, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Timeouts are applied per request through the context, see Do.
+	c := &http.Client{Transport: t, CheckRedirect: checkRedirect}
+	clients[cfg] = c
+	return c, nil
+}
+
+func newTransport(cfg Transport) (*http.Transport, error) {
+	t := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+	}
+	if cfg.DisableHTTP2 {
+		// A non-nil, empty map turns off HTTP/2 negotiation.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	if cfg.Proxy != "" {
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.Proxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	tc := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s: no PEM certificates found", cfg.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		keyFile := cfg.KeyFile
+		if keyFile == "" {
+			keyFile = cfg.CertFile
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tc
+	return t, nil
+}
@@ -0,0 +1,180 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Equal reports whether two JSON-like values are equal. Numbers compare by
+// value whatever their Go type, and a string equals a number or bool whose
+// text form it spells ("5" == 5), so templated kwargs compare naturally.
+func Equal(a, b any) bool {
+	if fa, ok := number(a); ok {
+		if fb, ok := number(b); ok {
+			return fa == fb
+		}
+	}
+
+	switch ta := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		switch tb := b.(type) {
+		case string:
+			return ta == tb
+		case bool:
+			return strconv.FormatBool(tb) == ta
+		}
+		if fb, ok := number(b); ok {
+			fa, ok := numericString(ta)
+			return ok && fa == fb
+		}
+		return false
+	case bool:
+		if bb, ok := b.(bool); ok {
+			return ta == bb
+		}
+		if sb, ok := b.(string); ok {
+			return strconv.FormatBool(ta) == sb
+		}
+		return false
+	case []any:
+		tb, ok := b.([]any)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !Equal(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any, map[any]any:
+		ma, mb := asMap(a), asMap(b)
+		if mb == nil || len(ma) != len(mb) {
+			return false
+		}
+		for k, va := range ma {
+			vb, ok := mb[k]
+			if !ok || !Equal(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// numbers vs strings
+	if _, ok := b.(string); ok {
+		return Equal(b, a)
+	}
+	return false
+}
+
+// Compare orders two numbers (or numeric strings) or two strings. ok is false
+// when the values are not comparable.
+func Compare(a, b any) (int, bool) {
+	fa, aNum := number(a)
+	fb, bNum := number(b)
+	if !aNum {
+		fa, aNum = numericString(a)
+	}
+	if !bNum {
+		fb, bNum = numericString(b)
+	}
+	if aNum && bNum {
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	sa, aok := a.(string)
+	sb, bok := b.(string)
+	if aok && bok {
+		return strings.Compare(sa, sb), true
+	}
+	return 0, false
+}
+
+// TypeOf returns the JSON type name of v: string, number, boolean, object,
+// array or null.
+func TypeOf(v any) string {
+	if _, ok := number(v); ok {
+		return "number"
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any, map[any]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Format renders v as compact JSON for error messages.
+func Format(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func number(v any) (float64, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case uint:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case uint32:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+func numericString(v any) (float64, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
+
+func asMap(v any) map[string]any {
+	switch t := v.(type) {
+	case map[string]any:
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, vv := range t {
+			m[fmt.Sprint(k)] = vv
+		}
+		return m
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filter is a disjunction of conjunctions: a || b && c  ==  a || (b && c).
+type filter [][]cond
+
+type cond struct {
+	negate bool
+	left   operand
+	op     string // "" for an existence test
+	right  operand
+	re     *regexp.Regexp
+}
+
+type operand struct {
+	path    *Path // relative to @; nil for literals
+	literal any
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func parseFilter(expr string) (filter, error) {
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = expr[1 : len(expr)-1]
+	}
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	var f filter
+	for _, alt := range splitOutsideQuotes(expr, "||") {
+		var conj []cond
+		for _, part := range splitOutsideQuotes(alt, "&&") {
+			c, err := parseCond(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			conj = append(conj, c)
+		}
+		f = append(f, conj)
+	}
+	return f, nil
+}
+
+func parseCond(s string) (cond, error) {
+	for _, op := range operators {
+		i := indexOutsideQuotes(s, op)
+		if i < 0 {
+			continue
+		}
+		left, err := parseOperand(strings.TrimSpace(s[:i]))
+		if err != nil {
+			return cond{}, err
+		}
+		right, err := parseOperand(strings.TrimSpace(s[i+len(op):]))
+		if err != nil {
+			return cond{}, err
+		}
+		c := cond{left: left, op: op, right: right}
+		if op == "=~" {
+			pat, ok := right.literal.(string)
+			if !ok || right.path != nil {
+				return cond{}, fmt.Errorf("=~ needs a quoted regex on the right")
+			}
+			pat = strings.TrimSuffix(strings.TrimPrefix(pat, "/"), "/")
+			if c.re, err = regexp.Compile(pat); err != nil {
+				return cond{}, fmt.Errorf("invalid regex %q: %v", pat, err)
+			}
+		}
+		return c, nil
+	}
+
+	// existence test
+	c := cond{}
+	if strings.HasPrefix(s, "!") {
+		c.negate = true
+		s = strings.TrimSpace(s[1:])
+	}
+	o, err := parseOperand(s)
+	if err != nil {
+		return cond{}, err
+	}
+	if o.path == nil {
+		return cond{}, fmt.Errorf("filter %q must reference @", s)
+	}
+	c.left = o
+	return c, nil
+}
+
+func parseOperand(s string) (operand, error) {
+	switch {
+	case s == "":
+		return operand{}, fmt.Errorf("missing operand in filter")
+	case strings.HasPrefix(s, "@"):
+		p, err := Compile(s[1:])
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{path: p}, nil
+	case isQuoted(s):
+		return operand{literal: unquote(s)}, nil
+	case s == "true":
+		return operand{literal: true}, nil
+	case s == "false":
+		return operand{literal: false}, nil
+	case s == "null":
+		return operand{literal: nil}, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return operand{}, fmt.Errorf("invalid filter operand %q", s)
+	}
+	return operand{literal: f}, nil
+}
+
+func (o operand) eval(item any) (any, bool) {
+	if o.path == nil {
+		return o.literal, true
+	}
+	vals := o.path.Get(item)
+	if len(vals) == 0 {
+		return nil, false
+	}
+	return vals[0], true
+}
+
+func (f filter) match(item any) bool {
+	for _, conj := range f {
+		ok := true
+		for _, c := range conj {
+			if !c.match(item) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c cond) match(item any) bool {
+	l, lok := c.left.eval(item)
+	if c.op == "" {
+		truthy := lok && l != nil && l != false
+		return truthy != c.negate
+	}
+	r, rok := c.right.eval(item)
+	if !lok || !rok {
+		return c.op == "!=" && lok != rok
+	}
+
+	switch c.op {
+	case "==":
+		return Equal(l, r)
+	case "!=":
+		return !Equal(l, r)
+	case "=~":
+		s, ok := l.(string)
+		return ok && c.re.MatchString(s)
+	}
+
+	cmp, ok := Compare(l, r)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	for {
+		i := indexOutsideQuotes(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+func indexOutsideQuotes(s, sub string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,290 @@
+// Package jsonpath evaluates JSONPath-style expressions against decoded JSON
+// (map[string]any / []any trees).
+//
+// Supported syntax:
+//
+//	$.data.items[0].id     leading "$" is optional: data.items.0.id also works
+//	items[-1]              negative indexes count from the end
+//	items[*].id, data.*    wildcards over array elements / object values
+//	$..id                  recursive descent
+//	['odd key']            quoted keys
+//	items[?(@.active)]     filter: member exists and is not false/null
+//	items[?(@.price > 10 && @.tag == 'sale')]
+//
+// Filter operators: == != < <= > >= =~ (regex), combined with && and ||;
+// a leading ! negates an existence test.
+package jsonpath
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled expression.
+type Path struct {
+	raw   string
+	steps []step
+}
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+	stepFilter
+)
+
+type step struct {
+	kind      stepKind
+	key       string
+	index     int
+	filter    filter
+	recursive bool // apply to the node and all of its descendants ("..")
+}
+
+// Compile parses path.
+func Compile(path string) (*Path, error) {
+	s := strings.TrimSpace(path)
+	s = strings.TrimPrefix(s, "$")
+	p := &Path{raw: path}
+
+	for i, first := 0, true; i < len(s); first = false {
+		recursive := false
+		switch {
+		case strings.HasPrefix(s[i:], ".."):
+			recursive = true
+			i += 2
+		case s[i] == '.':
+			i++
+		case s[i] == '[', first:
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q at offset %d", path, s[i], i)
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("jsonpath %q: path ends with '.'", path)
+		}
+
+		if s[i] == '[' {
+			end := matchBracket(s, i)
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: unterminated '['", path)
+			}
+			st, err := parseBracket(s[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", path, err)
+			}
+			st.recursive = recursive
+			p.steps = append(p.steps, st)
+			i = end + 1
+			continue
+		}
+
+		j := i
+		for j < len(s) && s[j] != '.' && s[j] != '[' {
+			j++
+		}
+		name := s[i:j]
+		if name == "*" {
+			p.steps = append(p.steps, step{kind: stepWildcard, recursive: recursive})
+		} else {
+			p.steps = append(p.steps, step{kind: stepKey, key: name, recursive: recursive})
+		}
+		i = j
+	}
+	return p, nil
+}
+
+// Query compiles path and evaluates it against root.
+func Query(root any, path string) ([]any, error) {
+	p, err := Compile(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(root), nil
+}
+
+func (p *Path) String() string { return p.raw }
+
+// Definite reports whether the path can match at most one value, i.e. it
+// contains no wildcard, filter or recursive descent.
+func (p *Path) Definite() bool {
+	for _, st := range p.steps {
+		if st.recursive || st.kind == stepWildcard || st.kind == stepFilter {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns every value matched by the path, in document order.
+func (p *Path) Get(root any) []any {
+	cur := []any{root}
+	for _, st := range p.steps {
+		var next []any
+		for _, node := range cur {
+			if st.recursive {
+				for _, n := range descendants(node) {
+					next = append(next, st.apply(n)...)
+				}
+				continue
+			}
+			next = append(next, st.apply(node)...)
+		}
+		cur = next
+		if len(cur) == 0 {
+			break
+		}
+	}
+	return cur
+}
+
+func (st step) apply(node any) []any {
+	switch st.kind {
+	case stepKey:
+		if v, ok := member(node, st.key); ok {
+			return []any{v}
+		}
+		// numeric segments index arrays (data.items.0.id)
+		if arr, ok := node.([]any); ok {
+			if idx, err := strconv.Atoi(st.key); err == nil {
+				return index(arr, idx)
+			}
+		}
+	case stepIndex:
+		if arr, ok := node.([]any); ok {
+			return index(arr, st.index)
+		}
+	case stepWildcard:
+		return children(node)
+	case stepFilter:
+		if arr, ok := node.([]any); ok {
+			var out []any
+			for _, e := range arr {
+				if st.filter.match(e) {
+					out = append(out, e)
+				}
+			}
+			return out
+		}
+		if node != nil && st.filter.match(node) {
+			return []any{node}
+		}
+	}
+	return nil
+}
+
+func member(node any, key string) (any, bool) {
+	switch m := node.(type) {
+	case map[string]any:
+		v, ok := m[key]
+		return v, ok
+	case map[any]any:
+		for k, v := range m {
+			if fmt.Sprint(k) == key {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func index(arr []any, i int) []any {
+	if i < 0 {
+		i += len(arr)
+	}
+	if i < 0 || i >= len(arr) {
+		return nil
+	}
+	return []any{arr[i]}
+}
+
+// children returns array elements or object values (sorted by key).
+func children(node any) []any {
+	switch t := node.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		out := make([]any, 0, len(t))
+		for _, k := range slices.Sorted(maps.Keys(t)) {
+			out = append(out, t[k])
+		}
+		return out
+	case map[any]any:
+		out := make([]any, 0, len(t))
+		for _, v := range t {
+			out = append(out, v)
+		}
+		return out
+	}
+	return nil
+}
+
+// descendants returns node followed by every nested value, depth first.
+func descendants(node any) []any {
+	out := []any{node}
+	for _, c := range children(node) {
+		out = append(out, descendants(c)...)
+	}
+	return out
+}
+
+// matchBracket returns the index of the ']' closing the '[' at open.
+func matchBracket(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+			if depth == 0 && c == ']' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(content string) (step, error) {
+	c := strings.TrimSpace(content)
+	switch {
+	case c == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(c, "?"):
+		f, err := parseFilter(strings.TrimSpace(c[1:]))
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepFilter, filter: f}, nil
+	case isQuoted(c):
+		return step{kind: stepKey, key: unquote(c)}, nil
+	}
+	i, err := strconv.Atoi(c)
+	if err != nil {
+		return step{}, fmt.Errorf("invalid subscript [%s]", content)
+	}
+	return step{kind: stepIndex, index: i}, nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+func unquote(s string) string {
+	r := strings.NewReplacer(`\\`, `\`, `\'`, `'`, `\"`, `"`)
+	return r.Replace(s[1 : len(s)-1])
+}
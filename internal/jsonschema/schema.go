@@ -0,0 +1,245 @@
+// Package jsonschema validates decoded JSON against JSON Schema (draft 2020-12).
+//
+// Supported: type, enum, const, numeric/string/array/object constraints,
+// properties/patternProperties/additionalProperties, prefixItems/items/contains,
+// dependentRequired/dependentSchemas, propertyNames, allOf/anyOf/oneOf/not,
+// if/then/else, format (common formats are asserted), $defs/definitions, $id,
+// $anchor and $ref to the same document or to local files (JSON or YAML).
+// OpenAPI's `nullable: true` is honoured as well. unevaluated* keywords and
+// remote (http) references are not supported.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a root schema together with every document reachable through $ref.
+type Schema struct {
+	root any
+	base string // absolute URI of the root document
+
+	docs map[string]any // documents and $id-identified subschemas by URI
+}
+
+// Load reads a schema document (JSON or YAML) from path. A fragment such as
+// "spec.yaml#/components/schemas/User" selects a subschema.
+func Load(path string) (*Schema, error) {
+	file, frag, _ := strings.Cut(path, "#")
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{docs: map[string]any{}}
+	uri := fileURI(abs)
+	doc, err := s.load(uri)
+	if err != nil {
+		return nil, err
+	}
+	s.root, s.base = doc, uri
+	if frag != "" {
+		sub, err := pointer(doc, frag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		s.root = sub
+	}
+	return s, nil
+}
+
+// New wraps an already decoded schema. Relative $refs are resolved against baseDir.
+func New(doc any, baseDir string) (*Schema, error) {
+	abs, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	doc = normalize(doc)
+	s := &Schema{root: doc, base: fileURI(filepath.Join(abs, "inline-schema.json")), docs: map[string]any{}}
+	s.register(s.base, doc)
+	return s, nil
+}
+
+// Validate returns every violation found in instance; nil means valid.
+func (s *Schema) Validate(instance any) []ValidationError {
+	v := &validator{schema: s}
+	v.validate(s.root, s.base, normalize(instance), "", 0)
+	return v.errs
+}
+
+// ValidationError is a single violation located by a JSON pointer into the instance.
+type ValidationError struct {
+	InstancePath string
+	Message      string
+}
+
+func (e ValidationError) Error() string {
+	p := e.InstancePath
+	if p == "" {
+		p = "/"
+	}
+	return p + ": " + e.Message
+}
+
+// load reads and registers the document at uri (file scheme only).
+func (s *Schema) load(uri string) (any, error) {
+	if doc, ok := s.docs[uri]; ok {
+		return doc, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return nil, fmt.Errorf("cannot load %q: only local files are supported", uri)
+	}
+	data, err := os.ReadFile(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if strings.EqualFold(filepath.Ext(u.Path), ".json") {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", u.Path, err)
+	}
+	doc = normalize(doc)
+	s.register(uri, doc)
+	return doc, nil
+}
+
+// register indexes doc under uri, plus every nested $id and $anchor.
+func (s *Schema) register(uri string, doc any) {
+	s.docs[uri] = doc
+	s.index(uri, doc)
+}
+
+func (s *Schema) index(scope string, node any) {
+	m, ok := node.(map[string]any)
+	if !ok {
+		if arr, ok := node.([]any); ok {
+			for _, e := range arr {
+				s.index(scope, e)
+			}
+		}
+		return
+	}
+	if id, ok := m["$id"].(string); ok {
+		scope = resolveURI(scope, id)
+		key, _, _ := strings.Cut(scope, "#")
+		if _, exists := s.docs[key]; !exists {
+			s.docs[key] = m
+		}
+	}
+	if a, ok := m["$anchor"].(string); ok {
+		key, _, _ := strings.Cut(scope, "#")
+		s.docs[key+"#"+a] = m
+	}
+	for k, v := range m {
+		switch k {
+		case "enum", "const", "default", "examples", "example":
+			continue
+		}
+		s.index(scope, v)
+	}
+}
+
+// resolve returns the schema a $ref points to and its base URI.
+func (s *Schema) resolve(scope, ref string) (any, string, error) {
+	target := resolveURI(scope, ref)
+	docURI, frag, _ := strings.Cut(target, "#")
+
+	doc, ok := s.docs[docURI]
+	if !ok {
+		var err error
+		if doc, err = s.load(docURI); err != nil {
+			return nil, "", err
+		}
+	}
+	switch {
+	case frag == "":
+		return doc, docURI, nil
+	case strings.HasPrefix(frag, "/"):
+		sub, err := pointer(doc, frag)
+		return sub, docURI, err
+	default:
+		if sub, ok := s.docs[docURI+"#"+frag]; ok {
+			return sub, docURI, nil
+		}
+		return nil, "", fmt.Errorf("anchor %q not found", frag)
+	}
+}
+
+// pointer evaluates a JSON pointer ("/components/schemas/User") against doc.
+func pointer(doc any, ptr string) (any, error) {
+	ptr, _ = url.PathUnescape(ptr)
+	cur := doc
+	if ptr == "" || ptr == "/" {
+		return cur, nil
+	}
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		switch t := cur.(type) {
+		case map[string]any:
+			v, ok := t[tok]
+			if !ok {
+				return nil, fmt.Errorf("pointer %q: %q not found", ptr, tok)
+			}
+			cur = v
+		case []any:
+			var i int
+			if _, err := fmt.Sscanf(tok, "%d", &i); err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("pointer %q: bad index %q", ptr, tok)
+			}
+			cur = t[i]
+		default:
+			return nil, fmt.Errorf("pointer %q: cannot descend into %T", ptr, cur)
+		}
+	}
+	return cur, nil
+}
+
+func resolveURI(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+func fileURI(abs string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// normalize converts map[any]any (YAML) into map[string]any, recursively.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, vv := range t {
+			m[fmt.Sprint(k)] = normalize(vv)
+		}
+		return m
+	case map[string]any:
+		for k, vv := range t {
+			t[k] = normalize(vv)
+		}
+		return t
+	case []any:
+		for i, vv := range t {
+			t[i] = normalize(vv)
+		}
+		return t
+	}
+	return v
+}
@@ -0,0 +1,367 @@
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth bounds $ref chains that never consume the instance ({"$ref": "#"}).
+const maxDepth = 256
+
+type validator struct {
+	schema *Schema
+	errs   []ValidationError
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{InstancePath: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// valid runs schema against inst in isolation and reports whether it passed.
+func (v *validator) valid(schema any, scope string, inst any, path string, depth int) bool {
+	sub := &validator{schema: v.schema}
+	sub.validate(schema, scope, inst, path, depth)
+	return len(sub.errs) == 0
+}
+
+func (v *validator) validate(schema any, scope string, inst any, path string, depth int) {
+	if depth > maxDepth {
+		v.fail(path, "schema recursion too deep")
+		return
+	}
+
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	case map[string]any:
+		v.validateObject(s, scope, inst, path, depth)
+	case nil:
+		return
+	default:
+		v.fail(path, "invalid schema of type %T", schema)
+	}
+}
+
+func (v *validator) validateObject(s map[string]any, scope string, inst any, path string, depth int) {
+	if id, ok := s["$id"].(string); ok {
+		scope = resolveURI(scope, id)
+	}
+
+	for _, key := range []string{"$ref", "$dynamicRef"} {
+		ref, ok := s[key].(string)
+		if !ok {
+			continue
+		}
+		target, base, err := v.schema.resolve(scope, ref)
+		if err != nil {
+			v.fail(path, "%s %q: %v", key, ref, err)
+			continue
+		}
+		v.validate(target, base, inst, path, depth+1)
+	}
+
+	v.checkType(s, inst, path)
+	v.checkEnum(s, inst, path)
+
+	switch t := inst.(type) {
+	case string:
+		v.checkString(s, t, path)
+	case []any:
+		v.checkArray(s, scope, t, path, depth)
+	case map[string]any:
+		v.checkObject(s, scope, t, path, depth)
+	default:
+		if f, ok := number(inst); ok {
+			v.checkNumber(s, f, path)
+		}
+	}
+
+	v.checkCombinators(s, scope, inst, path, depth)
+}
+
+func (v *validator) checkType(s map[string]any, inst any, path string) {
+	raw, ok := s["type"]
+	if !ok {
+		return
+	}
+	var types []string
+	switch t := raw.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, e := range t {
+			if str, ok := e.(string); ok {
+				types = append(types, str)
+			}
+		}
+	}
+	if nullable, _ := s["nullable"].(bool); nullable {
+		types = append(types, "null")
+	}
+
+	got := typeOf(inst)
+	for _, want := range types {
+		if want == got || (want == "number" && got == "integer") {
+			return
+		}
+	}
+	v.fail(path, "expected %s, got %s", strings.Join(types, " or "), got)
+}
+
+func (v *validator) checkEnum(s map[string]any, inst any, path string) {
+	if c, ok := s["const"]; ok && !equal(inst, c) {
+		v.fail(path, "must be %s", format(c))
+	}
+	if enum, ok := s["enum"].([]any); ok {
+		for _, e := range enum {
+			if equal(inst, e) {
+				return
+			}
+		}
+		v.fail(path, "%s is not one of %s", format(inst), format(enum))
+	}
+}
+
+func (v *validator) checkNumber(s map[string]any, f float64, path string) {
+	if m, ok := number(s["minimum"]); ok && f < m {
+		v.fail(path, "%v is less than minimum %v", f, m)
+	}
+	if m, ok := number(s["maximum"]); ok && f > m {
+		v.fail(path, "%v is greater than maximum %v", f, m)
+	}
+	switch em := s["exclusiveMinimum"].(type) {
+	case bool: // draft 4 / OpenAPI 3.0 form
+		if m, ok := number(s["minimum"]); ok && em && f == m {
+			v.fail(path, "%v must be greater than %v", f, m)
+		}
+	default:
+		if m, ok := number(em); ok && f <= m {
+			v.fail(path, "%v must be greater than %v", f, m)
+		}
+	}
+	switch em := s["exclusiveMaximum"].(type) {
+	case bool:
+		if m, ok := number(s["maximum"]); ok && em && f == m {
+			v.fail(path, "%v must be less than %v", f, m)
+		}
+	default:
+		if m, ok := number(em); ok && f >= m {
+			v.fail(path, "%v must be less than %v", f, m)
+		}
+	}
+	if m, ok := number(s["multipleOf"]); ok && m > 0 {
+		q := f / m
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "%v is not a multiple of %v", f, m)
+		}
+	}
+}
+
+func (v *validator) checkString(s map[string]any, str string, path string) {
+	n := utf8.RuneCountInString(str)
+	if m, ok := integer(s["minLength"]); ok && n < m {
+		v.fail(path, "length %d is less than minLength %d", n, m)
+	}
+	if m, ok := integer(s["maxLength"]); ok && n > m {
+		v.fail(path, "length %d is greater than maxLength %d", n, m)
+	}
+	if p, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			v.fail(path, "invalid pattern %q: %v", p, err)
+		} else if !re.MatchString(str) {
+			v.fail(path, "%q does not match pattern %q", str, p)
+		}
+	}
+	if f, ok := s["format"].(string); ok {
+		if err := checkFormat(f, str); err != nil {
+			v.fail(path, "%q is not a valid %s: %v", str, f, err)
+		}
+	}
+}
+
+func (v *validator) checkArray(s map[string]any, scope string, arr []any, path string, depth int) {
+	if m, ok := integer(s["minItems"]); ok && len(arr) < m {
+		v.fail(path, "has %d items, fewer than minItems %d", len(arr), m)
+	}
+	if m, ok := integer(s["maxItems"]); ok && len(arr) > m {
+		v.fail(path, "has %d items, more than maxItems %d", len(arr), m)
+	}
+	if u, _ := s["uniqueItems"].(bool); u {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					v.fail(path, "items %d and %d are equal, but uniqueItems is set", i, j)
+				}
+			}
+		}
+	}
+
+	prefix, _ := s["prefixItems"].([]any)
+	items := s["items"]
+	if legacy, ok := items.([]any); ok { // pre-2020 tuple form
+		prefix, items = legacy, s["additionalItems"]
+	}
+	for i, e := range arr {
+		p := path + "/" + strconv.Itoa(i)
+		switch {
+		case i < len(prefix):
+			v.validate(prefix[i], scope, e, p, depth+1)
+		case items != nil:
+			v.validate(items, scope, e, p, depth+1)
+		}
+	}
+
+	if contains, ok := s["contains"]; ok {
+		matches := 0
+		for i, e := range arr {
+			if v.valid(contains, scope, e, path+"/"+strconv.Itoa(i), depth+1) {
+				matches++
+			}
+		}
+		min := 1
+		if m, ok := integer(s["minContains"]); ok {
+			min = m
+		}
+		if matches < min {
+			v.fail(path, "contains %d matching items, expected at least %d", matches, min)
+		}
+		if m, ok := integer(s["maxContains"]); ok && matches > m {
+			v.fail(path, "contains %d matching items, expected at most %d", matches, m)
+		}
+	}
+}
+
+func (v *validator) checkObject(s map[string]any, scope string, obj map[string]any, path string, depth int) {
+	if m, ok := integer(s["minProperties"]); ok && len(obj) < m {
+		v.fail(path, "has %d properties, fewer than minProperties %d", len(obj), m)
+	}
+	if m, ok := integer(s["maxProperties"]); ok && len(obj) > m {
+		v.fail(path, "has %d properties, more than maxProperties %d", len(obj), m)
+	}
+	if req, ok := s["required"].([]any); ok {
+		for _, r := range req {
+			if name, ok := r.(string); ok {
+				if _, present := obj[name]; !present {
+					v.fail(path, "missing required property %q", name)
+				}
+			}
+		}
+	}
+	if deps, ok := s["dependentRequired"].(map[string]any); ok {
+		for prop, raw := range deps {
+			if _, present := obj[prop]; !present {
+				continue
+			}
+			list, _ := raw.([]any)
+			for _, r := range list {
+				if name, ok := r.(string); ok {
+					if _, present := obj[name]; !present {
+						v.fail(path, "property %q requires %q", prop, name)
+					}
+				}
+			}
+		}
+	}
+	if deps, ok := s["dependentSchemas"].(map[string]any); ok {
+		for prop, sub := range deps {
+			if _, present := obj[prop]; present {
+				v.validate(sub, scope, obj, path, depth+1)
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	patterns, _ := s["patternProperties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+	names, hasNames := s["propertyNames"]
+
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		val := obj[key]
+		p := path + "/" + escapePointer(key)
+
+		if hasNames && !v.valid(names, scope, key, p, depth+1) {
+			v.fail(p, "property name %q is not allowed by propertyNames", key)
+		}
+
+		matched := false
+		if sub, ok := props[key]; ok {
+			matched = true
+			v.validate(sub, scope, val, p, depth+1)
+		}
+		for pat, sub := range patterns {
+			re, err := regexp.Compile(pat)
+			if err != nil {
+				v.fail(path, "invalid patternProperties key %q: %v", pat, err)
+				continue
+			}
+			if re.MatchString(key) {
+				matched = true
+				v.validate(sub, scope, val, p, depth+1)
+			}
+		}
+		if !matched && hasAdditional {
+			if b, ok := additional.(bool); ok && !b {
+				v.fail(p, "additional property %q is not allowed", key)
+				continue
+			}
+			v.validate(additional, scope, val, p, depth+1)
+		}
+	}
+}
+
+func (v *validator) checkCombinators(s map[string]any, scope string, inst any, path string, depth int) {
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(sub, scope, inst, path, depth+1)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.valid(sub, scope, inst, path, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any schema in anyOf")
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range oneOf {
+			if v.valid(sub, scope, inst, path, depth+1) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(path, "matches %d schemas in oneOf, expected exactly 1", n)
+		}
+	}
+	if not, ok := s["not"]; ok && v.valid(not, scope, inst, path, depth+1) {
+		v.fail(path, "must not match the schema in not")
+	}
+	if cond, ok := s["if"]; ok {
+		if v.valid(cond, scope, inst, path, depth+1) {
+			if then, ok := s["then"]; ok {
+				v.validate(then, scope, inst, path, depth+1)
+			}
+		} else if els, ok := s["else"]; ok {
+			v.validate(els, scope, inst, path, depth+1)
+		}
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,154 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// typeOf returns the JSON Schema type of a decoded value.
+func typeOf(v any) string {
+	if f, ok := number(v); ok {
+		if f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func number(v any) (float64, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+func integer(v any) (int, bool) {
+	f, ok := number(v)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// equal is strict JSON equality: numbers by value, no string coercion.
+func equal(a, b any) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	switch ta := a.(type) {
+	case []any:
+		tb, ok := b.([]any)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for i := range ta {
+			if !equal(ta[i], tb[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		tb, ok := b.(map[string]any)
+		if !ok || len(ta) != len(tb) {
+			return false
+		}
+		for k, va := range ta {
+			vb, ok := tb[k]
+			if !ok || !equal(va, vb) {
+				return false
+			}
+		}
+		return true
+	case nil, bool, string:
+		return a == b
+	}
+	return false
+}
+
+func format(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+var (
+	uuidRE     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameRE = regexp.MustCompile(`^(?i:[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)(\.(?i:[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?))*$`)
+)
+
+// checkFormat asserts the common "format" values; unknown formats pass.
+func checkFormat(f, s string) error {
+	var err error
+	switch f {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, s)
+	case "date":
+		_, err = time.Parse(time.DateOnly, s)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", s)
+	case "email":
+		_, err = mail.ParseAddress(s)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(s); err == nil && !u.IsAbs() {
+			err = fmt.Errorf("not absolute")
+		}
+	case "uri-reference":
+		_, err = url.Parse(s)
+	case "uuid":
+		if !uuidRE.MatchString(s) {
+			err = fmt.Errorf("bad layout")
+		}
+	case "ipv4":
+		if ip := net.ParseIP(s); ip == nil || ip.To4() == nil {
+			err = fmt.Errorf("bad address")
+		}
+	case "ipv6":
+		if ip := net.ParseIP(s); ip == nil || ip.To4() != nil {
+			err = fmt.Errorf("bad address")
+		}
+	case "hostname":
+		if len(s) > 253 || !hostnameRE.MatchString(s) {
+			err = fmt.Errorf("bad hostname")
+		}
+	case "regex":
+		_, err = regexp.Compile(s)
+	}
+	return err
+}
@@ -0,0 +1,276 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/IsmailCLN/tapir/internal/jsonschema"
+)
+
+// Checker validates live responses against a document and records which
+// operations were exercised. It is safe for concurrent use.
+type Checker struct {
+	doc       *Document
+	ops       []route
+	basePaths []string
+
+	mu      sync.Mutex
+	hits    map[int]int
+	schemas map[string]*jsonschema.Schema
+}
+
+type route struct {
+	op       Operation
+	re       *regexp.Regexp
+	literals int // literal segments; more specific routes win
+}
+
+// Coverage summarizes which operations were exercised.
+type Coverage struct {
+	Total     int
+	Exercised int
+	Missing   []Operation
+}
+
+func NewChecker(doc *Document) *Checker {
+	c := &Checker{doc: doc, hits: map[int]int{}, schemas: map[string]*jsonschema.Schema{}}
+	for _, op := range doc.Operations() {
+		c.ops = append(c.ops, compileRoute(op))
+	}
+	servers, _ := doc.raw["servers"].([]any)
+	for _, s := range servers {
+		m, _ := s.(map[string]any)
+		raw, _ := m["url"].(string)
+		if u, err := url.Parse(raw); err == nil {
+			if p := strings.TrimSuffix(u.Path, "/"); p != "" && !strings.Contains(p, "{") {
+				c.basePaths = append(c.basePaths, p)
+			}
+		}
+	}
+	return c
+}
+
+func compileRoute(op Operation) route {
+	var b strings.Builder
+	b.WriteString("^")
+	literals := 0
+	for _, seg := range strings.Split(strings.Trim(op.Path, "/"), "/") {
+		b.WriteString("/")
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			b.WriteString("[^/]+")
+			continue
+		}
+		literals++
+		b.WriteString(regexp.QuoteMeta(seg))
+	}
+	b.WriteString("/?$")
+	return route{op: op, re: regexp.MustCompile(b.String()), literals: literals}
+}
+
+// Check matches the exchange to an operation and returns every way the
+// response deviates from it. An unmatched request is itself a violation.
+func (c *Checker) Check(method string, u *url.URL, status int, header http.Header, body []byte) []error {
+	idx := c.match(method, u.Path)
+	if idx < 0 {
+		return []error{fmt.Errorf("%s %s does not match any operation in %s", method, u.Path, c.doc.Path)}
+	}
+	c.mu.Lock()
+	c.hits[idx]++
+	c.mu.Unlock()
+
+	op := c.ops[idx].op
+	where := op.Method + " " + op.Path
+
+	key, resp, ok := responseFor(op.Responses, status)
+	if !ok {
+		return []error{fmt.Errorf("%s: status %d is not declared", where, status)}
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(resp.Headers)) {
+		// OpenAPI ignores Content-Type header definitions; content covers it
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		h, v := resp.Headers[name], header.Get(name)
+		if v == "" {
+			if h.Required {
+				errs = append(errs, fmt.Errorf("%s %s: required header %s is missing", where, key, name))
+			}
+			continue
+		}
+		if h.pointer == "" {
+			continue
+		}
+		schema, err := c.schema(h.pointer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: header %s: %v", where, key, name, err))
+			continue
+		}
+		for _, verr := range schema.Validate(c.headerValue(v, h.Schema)) {
+			msg := verr.Message
+			if verr.InstancePath != "" { // an item of an array header
+				msg = verr.Error()
+			}
+			errs = append(errs, fmt.Errorf("%s %s: header %s: %s", where, key, name, msg))
+		}
+	}
+
+	if len(resp.Content) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return errs
+	}
+	ct := header.Get("Content-Type")
+	mt, ok := mediaFor(resp.Content, ct)
+	if !ok {
+		return append(errs, fmt.Errorf("%s %s: content type %q is not declared", where, key, ct))
+	}
+	if mt.pointer == "" || !IsJSON(mt.ContentType) {
+		return errs
+	}
+
+	schema, err := c.schema(mt.pointer)
+	if err != nil {
+		return append(errs, fmt.Errorf("%s %s: %v", where, key, err))
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return append(errs, fmt.Errorf("%s %s: invalid JSON body: %v", where, key, err))
+	}
+	for _, v := range schema.Validate(doc) {
+		errs = append(errs, fmt.Errorf("%s %s: body %v", where, key, v))
+	}
+	return errs
+}
+
+// Coverage reports the operations no request has matched so far.
+func (c *Checker) Coverage() Coverage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cov := Coverage{Total: len(c.ops)}
+	for i, r := range c.ops {
+		if c.hits[i] > 0 {
+			cov.Exercised++
+		} else {
+			cov.Missing = append(cov.Missing, r.op)
+		}
+	}
+	return cov
+}
+
+func (c *Checker) match(method, path string) int {
+	candidates := []string{path}
+	for _, bp := range c.basePaths {
+		if strings.HasPrefix(path, bp+"/") || path == bp {
+			candidates = append(candidates, strings.TrimPrefix(path, bp))
+		}
+	}
+
+	best, bestLiterals := -1, -1
+	for _, p := range candidates {
+		if p == "" {
+			p = "/"
+		}
+		for i, r := range c.ops {
+			if r.op.Method == strings.ToUpper(method) && r.re.MatchString(p) && r.literals > bestLiterals {
+				best, bestLiterals = i, r.literals
+			}
+		}
+	}
+	return best
+}
+
+func (c *Checker) schema(ptr string) (*jsonschema.Schema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.schemas[ptr]; ok {
+		return s, nil
+	}
+	s, err := jsonschema.Load(c.doc.Path + "#" + ptr)
+	if err != nil {
+		return nil, err
+	}
+	c.schemas[ptr] = s
+	return s, nil
+}
+
+// headerValue converts a header to the type its schema declares, so
+// "X-Rate-Limit: 100" validates against {type: integer}. Arrays use the
+// simple style, "a,b,c". Values that do not convert stay strings and fail
+// validation.
+func (c *Checker) headerValue(v string, schema any) any {
+	m, _ := c.doc.deref(schema).(map[string]any)
+	switch schemaType(m) {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return json.Number(v)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case "array":
+		var items []any
+		for _, part := range strings.Split(v, ",") {
+			items = append(items, c.headerValue(strings.TrimSpace(part), m["items"]))
+		}
+		return items
+	}
+	return v
+}
+
+// responseFor finds the declared response for status: exact code, then
+// range (2XX), then default.
+func responseFor(responses map[string]Response, status int) (string, Response, bool) {
+	for _, key := range []string{strconv.Itoa(status), strconv.Itoa(status/100) + "XX", "default"} {
+		for k, r := range responses {
+			if strings.EqualFold(k, key) {
+				return k, r, true
+			}
+		}
+	}
+	return "", Response{}, false
+}
+
+// mediaFor matches a Content-Type header against declared media types,
+// honouring wildcards such as application/* and */*.
+func mediaFor(content map[string]MediaType, ct string) (MediaType, bool) {
+	got, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		got = strings.ToLower(strings.TrimSpace(ct))
+	}
+	// exact matches first, then the most specific wildcard
+	keys := slices.Sorted(maps.Keys(content))
+	sort.SliceStable(keys, func(i, j int) bool { return strings.Count(keys[i], "*") < strings.Count(keys[j], "*") })
+	for _, k := range keys {
+		want, _, err := mime.ParseMediaType(k)
+		if err != nil {
+			want = strings.ToLower(k)
+		}
+		if mediaMatches(want, got) {
+			return content[k], true
+		}
+	}
+	return MediaType{}, false
+}
+
+func mediaMatches(pattern, got string) bool {
+	if pattern == "*/*" || pattern == got {
+		return true
+	}
+	pt, _, _ := strings.Cut(pattern, "/")
+	gt, _, _ := strings.Cut(got, "/")
+	return strings.HasSuffix(pattern, "/*") && pt == gt
+}
@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+)
+
+// ImportOptions controls Suites.
+type ImportOptions struct {
+	// SchemaPath is how generated expect_json_schema checks refer to the spec
+	// file, normally relative to the generated suite. Empty disables them.
+	SchemaPath string
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Suites turns every operation into a request, grouped into one suite per
+// (first) tag. URLs use ${base_url} and ${param} placeholders whose defaults
+// come from the spec, so any of them can be overridden with variables.
+func (d *Document) Suites(opts ImportOptions) []domain.TestSuite {
+	fallback := d.Title()
+	if fallback == "" {
+		fallback = "openapi"
+	}
+
+	bySuite := map[string][]domain.TestRequest{}
+	names := map[string]map[string]int{}
+	for _, op := range d.Operations() {
+		suite := fallback
+		if len(op.Tags) > 0 {
+			suite = op.Tags[0]
+		}
+		if names[suite] == nil {
+			names[suite] = map[string]int{}
+		}
+		req := d.request(op, opts)
+		req.Name = uniqueName(names[suite], req.Name)
+		bySuite[suite] = append(bySuite[suite], req)
+	}
+
+	suites := make([]domain.TestSuite, 0, len(bySuite))
+	for _, name := range slices.Sorted(maps.Keys(bySuite)) {
+		suites = append(suites, domain.TestSuite{Name: name, Requests: bySuite[name]})
+	}
+	return suites
+}
+
+func (d *Document) request(op Operation, opts ImportOptions) domain.TestRequest {
+	name := op.ID
+	if name == "" {
+		name = strings.ToLower(op.Method) + "_" + strings.Trim(nonWord.ReplaceAllString(op.Path, "_"), "_")
+	}
+
+	r := domain.TestRequest{
+		Name: name,
+		Req: domain.HTTPRequest{
+			Method: op.Method,
+			URL:    d.requestURL(op),
+		},
+	}
+
+	for _, p := range op.Parameters {
+		if p.In == "header" && p.Required {
+			if r.Req.Headers == nil {
+				r.Req.Headers = map[string]string{}
+			}
+			r.Req.Headers[p.Name] = placeholder(p.Name, d.ParameterValue(p))
+		}
+	}
+
+	if mt := op.RequestBody; mt != nil {
+		example := mt.Example
+		if example == nil {
+			example = d.Sample(mt.Schema)
+		}
+		switch {
+		case IsJSON(mt.ContentType):
+			r.Req.Body = example
+		case mt.ContentType == "application/x-www-form-urlencoded":
+			if m, ok := example.(map[string]any); ok {
+				r.Req.Form = m
+			}
+		default:
+			if s, ok := example.(string); ok {
+				r.Req.Body = s
+			}
+		}
+		if !IsJSON(mt.ContentType) {
+			r.Req.Headers = setHeader(r.Req.Headers, "Content-Type", mt.ContentType)
+		}
+	}
+
+	r.Expect = d.expectations(op, opts)
+	return r
+}
+
+// requestURL builds ${base_url:-server}/path with path and required query
+// parameters filled from examples.
+func (d *Document) requestURL(op Operation) string {
+	path := op.Path
+	var query []string
+	for _, p := range op.Parameters {
+		val := d.ParameterValue(p)
+		switch {
+		case p.In == "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", placeholder(p.Name, val))
+		case p.In == "query" && p.Required:
+			query = append(query, url.QueryEscape(p.Name)+"="+placeholder(p.Name, val))
+		}
+	}
+
+	u := "${base_url:-" + d.ServerURL() + "}" + path
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+	return u
+}
+
+func (d *Document) expectations(op Operation, opts ImportOptions) []domain.Expectation {
+	codes, primary := SuccessCodes(op.Responses)
+	var out []domain.Expectation
+
+	switch {
+	case len(codes) == 1:
+		out = append(out, domain.Expectation{
+			Type:   "expect_status_code_equals",
+			Kwargs: map[string]any{"code": codes[0]},
+		})
+	case len(codes) > 1:
+		out = append(out, domain.Expectation{
+			Type:   "expect_status_code_in",
+			Kwargs: map[string]any{"codes": codes},
+		})
+	case primary != "":
+		out = append(out, domain.Expectation{
+			Type:   "expect_status_code_between",
+			Kwargs: map[string]any{"min": 200, "max": 299},
+		})
+	}
+
+	if primary == "" || opts.SchemaPath == "" {
+		return out
+	}
+	resp := op.Responses[primary]
+	for _, ct := range slices.Sorted(maps.Keys(resp.Content)) {
+		mt := resp.Content[ct]
+		if !IsJSON(ct) || mt.Schema == nil {
+			continue
+		}
+		out = append(out, domain.Expectation{
+			Type:   "expect_json_schema",
+			Kwargs: map[string]any{"schema_file": mt.SchemaRef(opts.SchemaPath)},
+		})
+		break
+	}
+	return out
+}
+
+// SuccessCodes returns the explicitly declared 2xx codes (sorted) and the
+// response key that describes the expected success: the lowest 2xx code,
+// else "2XX", else "default". primary is "" when none exists.
+func SuccessCodes(responses map[string]Response) (codes []int, primary string) {
+	for key := range responses {
+		if c, err := strconv.Atoi(key); err == nil && c >= 200 && c < 300 {
+			codes = append(codes, c)
+		}
+	}
+	slices.Sort(codes)
+	switch {
+	case len(codes) > 0:
+		primary = strconv.Itoa(codes[0])
+	case hasKey(responses, "2XX"):
+		primary = "2XX"
+	case hasKey(responses, "default"):
+		primary = "default"
+	}
+	return codes, primary
+}
+
+func hasKey(responses map[string]Response, key string) bool {
+	_, ok := responses[key]
+	return ok
+}
+
+// placeholder renders ${name:-example}, or ${name} when there is no example.
+func placeholder(name string, example any) string {
+	if example == nil {
+		return "${" + name + "}"
+	}
+	return "${" + name + ":-" + fmt.Sprint(example) + "}"
+}
+
+func setHeader(h map[string]string, k, v string) map[string]string {
+	if h == nil {
+		h = map[string]string{}
+	}
+	h[k] = v
+	return h
+}
+
+func uniqueName(seen map[string]int, name string) string {
+	seen[name]++
+	if n := seen[name]; n > 1 {
+		return fmt.Sprintf("%s_%d", name, n)
+	}
+	return name
+}
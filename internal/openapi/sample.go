@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// maxSampleDepth stops recursive schemas from expanding forever.
+const maxSampleDepth = 8
+
+// Sample builds an example value for schema, preferring example, default,
+// const and enum values over synthesized placeholders.
+func (d *Document) Sample(schema any) any {
+	return d.sample(schema, 0)
+}
+
+func (d *Document) sample(schema any, depth int) any {
+	s, ok := d.deref(schema).(map[string]any)
+	if !ok || depth > maxSampleDepth {
+		return nil
+	}
+	for _, k := range []string{"example", "default", "const"} {
+		if v, ok := s[k]; ok {
+			return v
+		}
+	}
+	if ex := asSlice(s["examples"]); len(ex) > 0 {
+		return ex[0]
+	}
+	if enum := asSlice(s["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+	for _, k := range []string{"allOf", "oneOf", "anyOf"} {
+		subs := asSlice(s[k])
+		if len(subs) == 0 {
+			continue
+		}
+		if k != "allOf" {
+			return d.sample(subs[0], depth+1)
+		}
+		merged := map[string]any{}
+		for _, sub := range subs {
+			if m, ok := d.sample(sub, depth+1).(map[string]any); ok {
+				maps.Copy(merged, m)
+			}
+		}
+		return merged
+	}
+
+	switch schemaType(s) {
+	case "object":
+		out := map[string]any{}
+		props, _ := s["properties"].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			if ro, _ := d.deref(props[name]).(map[string]any)["readOnly"].(bool); ro {
+				continue
+			}
+			out[name] = d.sample(props[name], depth+1)
+		}
+		return out
+	case "array":
+		if item := d.sample(s["items"], depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	case "string":
+		return sampleString(s)
+	}
+	return nil
+}
+
+// ParameterValue returns an example value for p (example, then schema sample).
+func (d *Document) ParameterValue(p Parameter) any {
+	if p.Example != nil {
+		return p.Example
+	}
+	return d.Sample(p.Schema)
+}
+
+func schemaType(s map[string]any) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []any: // 3.1: ["string", "null"]
+		for _, e := range t {
+			if str, ok := e.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+	if _, ok := s["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func sampleString(s map[string]any) string {
+	f, _ := s["format"].(string)
+	switch f {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "c3RyaW5n"
+	}
+	return "string"
+}
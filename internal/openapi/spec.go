@@ -0,0 +1,335 @@
+// Package openapi reads OpenAPI 3.x documents and turns them into tapir
+// suites or uses them to check responses for conformance.
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a loaded OpenAPI document. The tree is kept generic so that
+// schemas can be handed to the jsonschema package unchanged.
+type Document struct {
+	Path string
+	raw  map[string]any
+}
+
+// Operation is a single method on a path with every $ref resolved except
+// inside schemas.
+type Operation struct {
+	Method      string // upper case
+	Path        string // template, e.g. /users/{id}
+	ID          string
+	Summary     string
+	Tags        []string
+	Parameters  []Parameter
+	RequestBody *MediaType // preferred media type of the request body, if any
+	Responses   map[string]Response
+
+	pointer string // JSON pointer of the operation within the document
+}
+
+type Parameter struct {
+	Name     string
+	In       string // path, query, header, cookie
+	Required bool
+	Schema   any
+	Example  any
+}
+
+type Response struct {
+	Headers map[string]Header
+	Content map[string]MediaType
+
+	pointer string
+}
+
+type Header struct {
+	Required bool
+	Schema   any
+
+	pointer string // JSON pointer of the schema, "" when there is none
+}
+
+type MediaType struct {
+	ContentType string
+	Schema      any
+	Example     any
+
+	pointer string // JSON pointer of the schema, "" when there is none
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Load reads an OpenAPI 3.x document from a JSON or YAML file.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	raw, ok := normalize(doc).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an OpenAPI document", path)
+	}
+	v, _ := raw["openapi"].(string)
+	if !strings.HasPrefix(v, "3.") {
+		return nil, fmt.Errorf("%s: unsupported OpenAPI version %q (need 3.x)", path, v)
+	}
+	return &Document{Path: path, raw: raw}, nil
+}
+
+// Title returns info.title.
+func (d *Document) Title() string {
+	info, _ := d.raw["info"].(map[string]any)
+	t, _ := info["title"].(string)
+	return t
+}
+
+// ServerURL returns the first servers[].url with variables set to their defaults.
+func (d *Document) ServerURL() string {
+	servers, _ := d.raw["servers"].([]any)
+	if len(servers) == 0 {
+		return ""
+	}
+	srv, _ := servers[0].(map[string]any)
+	u, _ := srv["url"].(string)
+	vars, _ := srv["variables"].(map[string]any)
+	for name, raw := range vars {
+		v, _ := raw.(map[string]any)
+		if def, ok := v["default"]; ok {
+			u = strings.ReplaceAll(u, "{"+name+"}", fmt.Sprint(def))
+		}
+	}
+	return strings.TrimSuffix(u, "/")
+}
+
+// Operations lists every operation, sorted by path then method.
+func (d *Document) Operations() []Operation {
+	paths, _ := d.raw["paths"].(map[string]any)
+	var ops []Operation
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		itemPtr := "/paths/" + escape(p)
+		item, _ := d.deref(paths[p]).(map[string]any)
+		shared := d.parameters(item["parameters"])
+		for _, m := range methods {
+			raw, ok := item[m].(map[string]any)
+			if !ok {
+				continue
+			}
+			ops = append(ops, d.operation(strings.ToUpper(m), p, itemPtr+"/"+m, raw, shared))
+		}
+	}
+	return ops
+}
+
+func (d *Document) operation(method, path, ptr string, raw map[string]any, shared []Parameter) Operation {
+	op := Operation{Method: method, Path: path, pointer: ptr}
+	op.ID, _ = raw["operationId"].(string)
+	op.Summary, _ = raw["summary"].(string)
+	for _, t := range asSlice(raw["tags"]) {
+		if s, ok := t.(string); ok {
+			op.Tags = append(op.Tags, s)
+		}
+	}
+
+	// operation-level parameters override path-level ones with the same name+in
+	own := d.parameters(raw["parameters"])
+	for _, p := range shared {
+		if !slices.ContainsFunc(own, func(o Parameter) bool { return o.Name == p.Name && o.In == p.In }) {
+			op.Parameters = append(op.Parameters, p)
+		}
+	}
+	op.Parameters = append(op.Parameters, own...)
+
+	if rb, ok := raw["requestBody"]; ok {
+		body, bodyPtr := d.derefPtr(rb, ptr+"/requestBody")
+		content, _ := body.(map[string]any)["content"].(map[string]any)
+		if mt, ok := d.preferredMedia(content, bodyPtr+"/content"); ok {
+			op.RequestBody = &mt
+		}
+	}
+
+	op.Responses = map[string]Response{}
+	responses, _ := raw["responses"].(map[string]any)
+	for code, r := range responses {
+		resp, respPtr := d.derefPtr(r, ptr+"/responses/"+escape(code))
+		rm, _ := resp.(map[string]any)
+		out := Response{Headers: map[string]Header{}, Content: map[string]MediaType{}, pointer: respPtr}
+		headers, _ := rm["headers"].(map[string]any)
+		for name, h := range headers {
+			hv, hPtr := d.derefPtr(h, respPtr+"/headers/"+escape(name))
+			hm, _ := hv.(map[string]any)
+			req, _ := hm["required"].(bool)
+			hdr := Header{Required: req, Schema: hm["schema"]}
+			if hdr.Schema != nil {
+				hdr.pointer = hPtr + "/schema"
+			}
+			out.Headers[name] = hdr
+		}
+		content, _ := rm["content"].(map[string]any)
+		for ct, m := range content {
+			out.Content[ct] = d.mediaType(ct, m, respPtr+"/content/"+escape(ct))
+		}
+		op.Responses[code] = out
+	}
+	return op
+}
+
+func (d *Document) parameters(raw any) []Parameter {
+	var out []Parameter
+	for _, r := range asSlice(raw) {
+		m, _ := d.deref(r).(map[string]any)
+		if m == nil {
+			continue
+		}
+		p := Parameter{Schema: m["schema"], Example: m["example"]}
+		p.Name, _ = m["name"].(string)
+		p.In, _ = m["in"].(string)
+		p.Required, _ = m["required"].(bool)
+		if p.Example == nil {
+			p.Example = firstExample(d, m["examples"])
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// preferredMedia picks JSON if available, otherwise the first content type.
+func (d *Document) preferredMedia(content map[string]any, ptr string) (MediaType, bool) {
+	keys := slices.Sorted(maps.Keys(content))
+	if len(keys) == 0 {
+		return MediaType{}, false
+	}
+	pick := keys[0]
+	for _, k := range keys {
+		if IsJSON(k) {
+			pick = k
+			break
+		}
+	}
+	return d.mediaType(pick, content[pick], ptr+"/"+escape(pick)), true
+}
+
+func (d *Document) mediaType(ct string, raw any, ptr string) MediaType {
+	m, _ := raw.(map[string]any)
+	mt := MediaType{ContentType: ct, Schema: m["schema"], Example: m["example"]}
+	if mt.Example == nil {
+		mt.Example = firstExample(d, m["examples"])
+	}
+	if mt.Schema != nil {
+		mt.pointer = ptr + "/schema"
+		// point straight at a referenced component when possible
+		if sm, ok := mt.Schema.(map[string]any); ok && len(sm) == 1 {
+			if ref, ok := sm["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+				mt.pointer = strings.TrimPrefix(ref, "#")
+			}
+		}
+	}
+	return mt
+}
+
+// SchemaRef returns a "file#pointer" reference to the media type's schema,
+// suitable for expect_json_schema's schema_file, or "" when there is no schema.
+func (m MediaType) SchemaRef(specPath string) string {
+	if m.pointer == "" {
+		return ""
+	}
+	return specPath + "#" + m.pointer
+}
+
+// deref follows a local "$ref" (repeatedly) and returns the target.
+func (d *Document) deref(v any) any {
+	out, _ := d.derefPtr(v, "")
+	return out
+}
+
+func (d *Document) derefPtr(v any, ptr string) (any, string) {
+	for range 32 {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, ptr
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return v, ptr
+		}
+		ptr = strings.TrimPrefix(ref, "#")
+		v = d.lookup(ptr)
+	}
+	return v, ptr
+}
+
+// lookup evaluates a JSON pointer against the document.
+func (d *Document) lookup(ptr string) any {
+	ptr, _ = url.PathUnescape(ptr)
+	var cur any = d.raw
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[tok]
+	}
+	return cur
+}
+
+func firstExample(d *Document, raw any) any {
+	examples, _ := raw.(map[string]any)
+	for _, k := range slices.Sorted(maps.Keys(examples)) {
+		ex, _ := d.deref(examples[k]).(map[string]any)
+		if v, ok := ex["value"]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// IsJSON reports whether a media type carries JSON (application/json, +json).
+func IsJSON(ct string) bool {
+	ct = strings.ToLower(strings.TrimSpace(strings.Split(ct, ";")[0]))
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+func escape(tok string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(tok)
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+// normalize converts map[any]any (YAML integer keys such as response codes)
+// into map[string]any, recursively.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, vv := range t {
+			m[fmt.Sprint(k)] = normalize(vv)
+		}
+		return m
+	case map[string]any:
+		for k, vv := range t {
+			t[k] = normalize(vv)
+		}
+		return t
+	case []any:
+		for i, vv := range t {
+			t[i] = normalize(vv)
+		}
+		return t
+	}
+	return v
+}
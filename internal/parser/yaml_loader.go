@@ -17,5 +17,8 @@ func LoadTestSuite(path string) ([]domain.TestSuite, error) {
 	if err := yaml.Unmarshal(data, &suites); err != nil {
 		return nil, err
 	}
+	for i := range suites {
+		suites[i].Path = path
+	}
 	return suites, nil
 }
@@ -0,0 +1,18 @@
+package parser
+
+import (
+	"io"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteTestSuites encodes suites in the same format LoadTestSuite reads.
+func WriteTestSuites(w io.Writer, suites []domain.TestSuite) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,167 @@
+// Package postman converts Postman v2.1 collections (and environments) into
+// tapir suites.
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Collection is the subset of the v2.1 format the importer understands.
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable"`
+	Auth     *Auth      `json:"auth"`
+}
+
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is either a folder (Item set) or a request (Request set).
+type Item struct {
+	Name    string   `json:"name"`
+	Item    []Item   `json:"item"`
+	Request *Request `json:"request"`
+	Event   []Event  `json:"event"`
+	Auth    *Auth    `json:"auth"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	Header []KV   `json:"header"`
+	URL    URL    `json:"url"`
+	Body   *Body  `json:"body"`
+	Auth   *Auth  `json:"auth"`
+}
+
+// URL accepts both the string and the object form.
+type URL struct {
+	Raw string `json:"raw"`
+}
+
+func (u *URL) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		u.Raw = s
+		return nil
+	}
+	type plain URL
+	return json.Unmarshal(b, (*plain)(u))
+}
+
+type KV struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type"` // formdata: text | file
+	Src      any    `json:"src"`  // formdata file path(s)
+	Disabled bool   `json:"disabled"`
+}
+
+type Body struct {
+	Mode       string `json:"mode"` // raw | urlencoded | formdata | file | graphql
+	Raw        string `json:"raw"`
+	URLEncoded []KV   `json:"urlencoded"`
+	FormData   []KV   `json:"formdata"`
+	File       struct {
+		Src string `json:"src"`
+	} `json:"file"`
+	GraphQL *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+// Auth keeps each strategy's parameters as a key/value list, as Postman does.
+type Auth struct {
+	Type   string `json:"type"`
+	Bearer []KV   `json:"bearer"`
+	Basic  []KV   `json:"basic"`
+	APIKey []KV   `json:"apikey"`
+}
+
+type Event struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec any `json:"exec"` // string or []string
+	} `json:"script"`
+}
+
+type Variable struct {
+	Key     string `json:"key"`
+	Value   any    `json:"value"`
+	Type    string `json:"type"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// Environment is a Postman environment export.
+type Environment struct {
+	Name   string     `json:"name"`
+	Values []Variable `json:"values"`
+}
+
+// LoadCollection reads a v2.x collection file.
+func LoadCollection(path string) (*Collection, error) {
+	var c Collection
+	if err := readJSON(path, &c); err != nil {
+		return nil, err
+	}
+	if c.Info.Schema != "" && !strings.Contains(c.Info.Schema, "v2.") {
+		return nil, fmt.Errorf("%s: unsupported collection schema %s (need v2.1)", path, c.Info.Schema)
+	}
+	return &c, nil
+}
+
+// LoadEnvironment reads an environment export.
+func LoadEnvironment(path string) (*Environment, error) {
+	var e Environment
+	if err := readJSON(path, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func param(list []KV, key string) string {
+	for _, kv := range list {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return ""
+}
+
+func (e Event) lines() []string {
+	switch t := e.Script.Exec.(type) {
+	case string:
+		return strings.Split(t, "\n")
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, l := range t {
+			if s, ok := l.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+)
+
+var (
+	varRE = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+	// pm.response.to.have.status(200)
+	statusRE = regexp.MustCompile(`pm\.response\.to\.(?:have|be)\.status\(\s*(\d{3})\s*\)`)
+	// pm.expect(pm.response.code).to.eql(200) / .equal(200) / .eq(200)
+	codeEqRE = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.code\s*\)\.to\.(?:be\.)?(?:eql|equal|eq)\(\s*(\d{3})\s*\)`)
+	// pm.expect(pm.response.code).to.be.oneOf([200, 201])
+	codeInRE = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.code\s*\)\.to\.be\.oneOf\(\s*\[([\d\s,]+)\]\s*\)`)
+	// pm.response.code === 200
+	codeCmpRE = regexp.MustCompile(`pm\.response\.code\s*===?\s*(\d{3})`)
+)
+
+// Convert turns the collection into suites: every folder becomes a suite
+// (nested folders are named "parent / child"), requests outside folders go
+// to a suite named after the collection. {{var}} becomes ${var}, with the
+// collection or environment value as the default when one is known.
+func Convert(c *Collection, env *Environment) []domain.TestSuite {
+	cv := &converter{vars: map[string]string{}, secrets: map[string]bool{}}
+	for _, v := range c.Variable {
+		cv.addVar(v)
+	}
+	if env != nil {
+		for _, v := range env.Values {
+			cv.addVar(v)
+		}
+	}
+
+	root := c.Info.Name
+	if root == "" {
+		root = "postman"
+	}
+	cv.walk(root, "", c.Item, c.Auth)
+
+	suites := make([]domain.TestSuite, 0, len(cv.order))
+	for _, name := range cv.order {
+		suites = append(suites, domain.TestSuite{Name: name, Requests: cv.suites[name]})
+	}
+	return suites
+}
+
+type converter struct {
+	vars    map[string]string
+	secrets map[string]bool
+
+	order  []string
+	suites map[string][]domain.TestRequest
+	names  map[string]map[string]int
+}
+
+func (cv *converter) addVar(v Variable) {
+	if v.Enabled != nil && !*v.Enabled {
+		return
+	}
+	if v.Type == "secret" {
+		// never copy secret values into suite files
+		cv.secrets[v.Key] = true
+		delete(cv.vars, v.Key)
+		return
+	}
+	if v.Value != nil {
+		cv.vars[v.Key] = fmt.Sprint(v.Value)
+	}
+}
+
+func (cv *converter) walk(root, prefix string, items []Item, auth *Auth) {
+	suite := root
+	if prefix != "" {
+		suite = prefix
+	}
+	for _, it := range items {
+		if it.Request == nil {
+			name := it.Name
+			if prefix != "" {
+				name = prefix + " / " + it.Name
+			}
+			inherited := auth
+			if it.Auth != nil {
+				inherited = it.Auth
+			}
+			cv.walk(root, name, it.Item, inherited)
+			continue
+		}
+		req := cv.request(it, auth)
+		cv.add(suite, req)
+	}
+}
+
+func (cv *converter) add(suite string, r domain.TestRequest) {
+	if cv.suites == nil {
+		cv.suites = map[string][]domain.TestRequest{}
+		cv.names = map[string]map[string]int{}
+	}
+	if _, ok := cv.suites[suite]; !ok {
+		cv.order = append(cv.order, suite)
+		cv.names[suite] = map[string]int{}
+	}
+	cv.names[suite][r.Name]++
+	if n := cv.names[suite][r.Name]; n > 1 {
+		r.Name = fmt.Sprintf("%s_%d", r.Name, n)
+	}
+	cv.suites[suite] = append(cv.suites[suite], r)
+}
+
+func (cv *converter) request(it Item, inherited *Auth) domain.TestRequest {
+	pr := it.Request
+	method := strings.ToUpper(pr.Method)
+	if method == "" {
+		method = "GET"
+	}
+	r := domain.TestRequest{
+		Name: it.Name,
+		Req: domain.HTTPRequest{
+			Method: method,
+			URL:    cv.template(pr.URL.Raw),
+		},
+	}
+
+	for _, h := range pr.Header {
+		if h.Disabled {
+			continue
+		}
+		r.Req.Headers = setHeader(r.Req.Headers, h.Key, cv.template(h.Value))
+	}
+
+	auth := inherited
+	if pr.Auth != nil {
+		auth = pr.Auth
+	}
+	cv.applyAuth(&r.Req, auth)
+	cv.applyBody(&r.Req, pr.Body)
+
+	for _, ev := range it.Event {
+		if ev.Listen == "test" {
+			r.Expect = append(r.Expect, statusChecks(ev.lines())...)
+		}
+	}
+	return r
+}
+
+func (cv *converter) applyAuth(req *domain.HTTPRequest, a *Auth) {
+	if a == nil {
+		return
+	}
+	switch a.Type {
+	case "bearer":
+		req.Headers = setHeader(req.Headers, "Authorization", "Bearer "+cv.template(param(a.Bearer, "token")))
+	case "apikey":
+		key, value := param(a.APIKey, "key"), cv.template(param(a.APIKey, "value"))
+		if param(a.APIKey, "in") == "query" {
+			sep := "?"
+			if strings.Contains(req.URL, "?") {
+				sep = "&"
+			}
+			// placeholders are kept as-is so the runner can expand them
+			if !strings.Contains(value, "${") {
+				value = url.QueryEscape(value)
+			}
+			req.URL += sep + url.QueryEscape(key) + "=" + value
+		} else {
+			req.Headers = setHeader(req.Headers, key, value)
+		}
+	case "basic":
+		req.Auth = &domain.Auth{
+			Type:     domain.AuthBasic,
+			Username: cv.template(param(a.Basic, "username")),
+			Password: cv.template(param(a.Basic, "password")),
+		}
+	}
+}
+
+func (cv *converter) applyBody(req *domain.HTTPRequest, b *Body) {
+	if b == nil {
+		return
+	}
+	switch b.Mode {
+	case "raw":
+		raw := cv.template(b.Raw)
+		if b.Options.Raw.Language == "json" {
+			// keep JSON bodies structured when they parse (no {{var}} in non-string spots)
+			var v any
+			if err := json.Unmarshal([]byte(raw), &v); err == nil {
+				req.Body = v
+				return
+			}
+		}
+		if raw != "" {
+			req.Body = raw
+		}
+	case "urlencoded":
+		for _, kv := range b.URLEncoded {
+			if kv.Disabled {
+				continue
+			}
+			if req.Form == nil {
+				req.Form = map[string]any{}
+			}
+			req.Form[kv.Key] = cv.template(kv.Value)
+		}
+	case "formdata":
+		for _, kv := range b.FormData {
+			if kv.Disabled {
+				continue
+			}
+			part := domain.MultipartPart{Name: kv.Key}
+			if kv.Type == "file" {
+				part.File = srcPath(kv.Src)
+			} else {
+				part.Value = cv.template(kv.Value)
+			}
+			req.Multipart = append(req.Multipart, part)
+		}
+	case "file":
+		req.BodyFile = b.File.Src
+	case "graphql":
+		if b.GraphQL == nil {
+			return
+		}
+		body := map[string]any{"query": cv.template(b.GraphQL.Query)}
+		var vars any
+		if err := json.Unmarshal([]byte(b.GraphQL.Variables), &vars); err == nil {
+			body["variables"] = vars
+		}
+		req.Body = body
+	}
+}
+
+// template rewrites {{name}} as ${name:-value} (or ${name} when the value is
+// unknown or secret). Postman's dynamic variables ({{$guid}}) are kept as-is.
+func (cv *converter) template(s string) string {
+	return varRE.ReplaceAllStringFunc(s, func(m string) string {
+		name := strings.TrimSpace(varRE.FindStringSubmatch(m)[1])
+		if strings.HasPrefix(name, "$") {
+			return m
+		}
+		if v, ok := cv.vars[name]; ok && !cv.secrets[name] && !strings.ContainsAny(v, "{}") {
+			return "${" + name + ":-" + v + "}"
+		}
+		return "${" + name + "}"
+	})
+}
+
+// statusChecks recognises the common pm.test status assertions.
+func statusChecks(lines []string) []domain.Expectation {
+	script := strings.Join(lines, "\n")
+	var out []domain.Expectation
+	seen := map[int]bool{}
+
+	for _, re := range []*regexp.Regexp{statusRE, codeEqRE, codeCmpRE} {
+		for _, m := range re.FindAllStringSubmatch(script, -1) {
+			code, _ := strconv.Atoi(m[1])
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			out = append(out, domain.Expectation{
+				Type:   "expect_status_code_equals",
+				Kwargs: map[string]any{"code": code},
+			})
+		}
+	}
+	for _, m := range codeInRE.FindAllStringSubmatch(script, -1) {
+		var codes []int
+		for _, f := range strings.Split(m[1], ",") {
+			if c, err := strconv.Atoi(strings.TrimSpace(f)); err == nil {
+				codes = append(codes, c)
+			}
+		}
+		if len(codes) > 0 {
+			out = append(out, domain.Expectation{
+				Type:   "expect_status_code_in",
+				Kwargs: map[string]any{"codes": codes},
+			})
+		}
+	}
+	return out
+}
+
+func srcPath(src any) string {
+	switch t := src.(type) {
+	case string:
+		return t
+	case []any:
+		if len(t) > 0 {
+			return fmt.Sprint(t[0])
+		}
+	}
+	return ""
+}
+
+func setHeader(h map[string]string, k, v string) map[string]string {
+	if h == nil {
+		h = map[string]string{}
+	}
+	h[k] = v
+	return h
+}
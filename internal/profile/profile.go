@@ -0,0 +1,86 @@
+// Package profile loads environment profiles (dev, staging, prod, …) that
+// seed the template variables of a run.
+package profile
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is looked up next to the suite, then in the working directory.
+const DefaultFile = "tapir.env.yaml"
+
+// Profile is one named section of an environment file:
+//
+//	staging:
+//	  base_url: https://staging.example.com
+//	  vars:
+//	    tenant: acme
+type Profile struct {
+	BaseURL string            `yaml:"base_url"`
+	Vars    map[string]string `yaml:"vars"`
+}
+
+// File maps profile names to profiles.
+type File map[string]Profile
+
+// Load reads an environment file.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Find returns the environment file for a suite at suitePath: DefaultFile in
+// the suite's directory, else in the working directory, else "".
+func Find(suitePath string) string {
+	for _, dir := range []string{filepath.Dir(suitePath), "."} {
+		p := filepath.Join(dir, DefaultFile)
+		if st, err := os.Stat(p); err == nil && st.Mode().IsRegular() {
+			return p
+		}
+	}
+	return ""
+}
+
+// Get returns the named profile.
+func (f File) Get(name string) (Profile, error) {
+	p, ok := f[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown environment %q (available: %s)", name,
+			strings.Join(slices.Sorted(maps.Keys(f)), ", "))
+	}
+	return p, nil
+}
+
+// Variables returns the template variables of the profile named name:
+// its vars, plus base_url and env (the profile name) unless vars sets them.
+func (p Profile) Variables(name string) map[string]string {
+	vars := map[string]string{"env": name}
+	if p.BaseURL != "" {
+		vars["base_url"] = strings.TrimSuffix(p.BaseURL, "/")
+	}
+	maps.Copy(vars, p.Vars)
+	return vars
+}
+
+// ParseVar parses a --var flag value of the form key=value.
+func ParseVar(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", "", fmt.Errorf("invalid --var %q, expected key=value", s)
+	}
+	return strings.TrimSpace(key), value, nil
+}
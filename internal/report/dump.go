@@ -0,0 +1,80 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+// maxDumpBody caps each body in a dump so huge payloads don't bloat reports.
+const maxDumpBody = 8 << 10
+
+// dump renders the request and response behind r in a curl -v like layout.
+func dump(r runner.Result) string {
+	var b strings.Builder
+	if r.Attempts > 1 {
+		fmt.Fprintf(&b, "* last of %d attempts\n", r.Attempts)
+	}
+	if s := r.Sent; s != nil {
+		fmt.Fprintf(&b, "> %s %s\n", s.Method, s.URL)
+		writeHeaders(&b, "> ", s.Headers)
+		writeBody(&b, s.Body)
+	}
+	if rc := r.Received; rc != nil {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "< %d %s\n", rc.Status, http.StatusText(rc.Status))
+		writeHeaders(&b, "< ", rc.Headers)
+		writeBody(&b, rc.Body)
+	}
+	return b.String()
+}
+
+func writeHeaders(b *strings.Builder, prefix string, h http.Header) {
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[k] {
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, k, v)
+		}
+	}
+}
+
+func writeBody(b *strings.Builder, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	b.WriteString("\n")
+	b.WriteString(excerpt(body, maxDumpBody))
+	b.WriteString("\n")
+}
+
+// excerpt returns body as text, cut after limit bytes. Binary bodies are
+// replaced by a size note.
+func excerpt(body []byte, limit int) string {
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("[%d bytes of binary data]", len(body))
+	}
+	if len(body) <= limit {
+		return string(body)
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s… [%d more bytes]", body[:cut], len(body)-cut)
+}
+
+// create opens path for writing, creating parent directories as needed.
+func create(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
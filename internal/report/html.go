@@ -0,0 +1,283 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/httpclient"
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+// maxHTMLBody caps each body shown in the HTML report.
+const maxHTMLBody = 64 << 10
+
+var icons = map[string]string{"pass": "✓", "fail": "✗", "skip": "○"}
+
+//go:embed html.tmpl
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":   func(d time.Duration) string { return d.Round(time.Millisecond / 10).String() },
+	"icon": func(status string) string { return icons[status] },
+}).Parse(htmlSource))
+
+type htmlReport struct {
+	Generated               time.Time
+	Elapsed                 time.Duration
+	Passed, Failed, Skipped int
+	Suites                  []*htmlSuite
+	MaxDuration             time.Duration
+}
+
+type htmlSuite struct {
+	Name                    string
+	Passed, Failed, Skipped int
+	Requests                []*htmlRequest
+}
+
+type htmlRequest struct {
+	Name     string
+	Duration time.Duration
+	Attempts int
+	Sent     *htmlMessage
+	Received *htmlMessage
+	Checks   []htmlCheck
+	Search   string
+	Timing   *httpclient.Timing
+	Phases   []htmlPhase
+}
+
+// htmlPhase is one segment of a request's timing bar.
+type htmlPhase struct {
+	Name     string
+	Duration time.Duration
+	Percent  string
+}
+
+type htmlMessage struct {
+	Line    string
+	Headers [][2]string
+	Body    string
+}
+
+type htmlCheck struct {
+	Name   string
+	Status string // pass | fail | skip
+	Kwargs string
+	Error  string
+}
+
+// htmlWriter collects results and renders a single self-contained HTML file
+// on Close: a suite → request → expectation tree with filters and search.
+type htmlWriter struct {
+	f      *os.File
+	start  time.Time
+	report htmlReport
+	suites map[string]*htmlSuite
+	reqs   map[[2]string]*htmlRequest
+}
+
+func newHTML(path string) (Writer, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &htmlWriter{
+		f:      f,
+		start:  time.Now(),
+		suites: map[string]*htmlSuite{},
+		reqs:   map[[2]string]*htmlRequest{},
+	}, nil
+}
+
+func (h *htmlWriter) Write(r runner.Result) error {
+	s, ok := h.suites[r.Suite]
+	if !ok {
+		s = &htmlSuite{Name: r.Suite}
+		h.suites[r.Suite] = s
+		h.report.Suites = append(h.report.Suites, s)
+	}
+
+	key := [2]string{r.Suite, r.Request}
+	req, ok := h.reqs[key]
+	if !ok {
+		req = &htmlRequest{Name: r.Request}
+		h.reqs[key] = req
+		s.Requests = append(s.Requests, req)
+	}
+	if req.Sent == nil && r.Sent != nil {
+		req.Sent = &htmlMessage{
+			Line:    r.Sent.Method + " " + r.Sent.URL,
+			Headers: headerPairs(r.Sent.Headers),
+			Body:    pretty(r.Sent.Body),
+		}
+	}
+	if req.Received == nil && r.Received != nil {
+		req.Received = &htmlMessage{
+			Line:    strings.TrimSpace(strconv.Itoa(r.Received.Status) + " " + http.StatusText(r.Received.Status)),
+			Headers: headerPairs(r.Received.Headers),
+			Body:    pretty(r.Received.Body),
+		}
+	}
+	req.Attempts = max(req.Attempts, r.Attempts)
+	if r.Duration > req.Duration {
+		req.Duration = r.Duration
+		req.Timing = r.Timing
+		h.report.MaxDuration = max(h.report.MaxDuration, r.Duration)
+	}
+
+	c := htmlCheck{Name: r.TestName, Status: "pass"}
+	if len(r.Kwargs) > 0 {
+		if data, err := json.Marshal(r.Kwargs); err == nil {
+			c.Kwargs = string(data)
+		}
+	}
+	if r.Err != nil {
+		c.Error = helpers.Sanitize(r.Err.Error())
+	}
+	switch r.Status {
+	case runner.StatusPassed:
+		s.Passed++
+		h.report.Passed++
+	case runner.StatusFailed:
+		c.Status = "fail"
+		s.Failed++
+		h.report.Failed++
+	case runner.StatusSkipped:
+		c.Status = "skip"
+		s.Skipped++
+		h.report.Skipped++
+	}
+	req.Checks = append(req.Checks, c)
+	return nil
+}
+
+// Status is fail if any check failed, skip if all were skipped, else pass.
+func (s *htmlSuite) Status() string {
+	return overall(s.Failed, s.Passed, s.Skipped)
+}
+
+// Status is fail if any check failed, skip if all were skipped, else pass.
+func (r *htmlRequest) Status() string {
+	var passed, failed, skipped int
+	for _, c := range r.Checks {
+		switch c.Status {
+		case "pass":
+			passed++
+		case "fail":
+			failed++
+		default:
+			skipped++
+		}
+	}
+	return overall(failed, passed, skipped)
+}
+
+func overall(failed, passed, skipped int) string {
+	switch {
+	case failed > 0:
+		return "fail"
+	case passed == 0 && skipped > 0:
+		return "skip"
+	default:
+		return "pass"
+	}
+}
+
+func (h *htmlWriter) Close() error {
+	h.report.Generated = time.Now()
+	h.report.Elapsed = time.Since(h.start)
+	for _, s := range h.report.Suites {
+		for _, req := range s.Requests {
+			req.Search = searchText(s.Name, req)
+			req.Phases = phases(req, h.report.MaxDuration)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, h.report); err != nil {
+		h.f.Close()
+		return err
+	}
+	if _, err := h.f.Write(buf.Bytes()); err != nil {
+		h.f.Close()
+		return err
+	}
+	return h.f.Close()
+}
+
+// searchText is what the search box matches against, lower-cased.
+func searchText(suite string, req *htmlRequest) string {
+	parts := []string{suite, req.Name}
+	if req.Sent != nil {
+		parts = append(parts, req.Sent.Line)
+	}
+	for _, c := range req.Checks {
+		parts = append(parts, c.Name, c.Error)
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
+
+func headerPairs(h http.Header) [][2]string {
+	var pairs [][2]string
+	for _, k := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[k] {
+			pairs = append(pairs, [2]string{k, v})
+		}
+	}
+	return pairs
+}
+
+// pretty indents JSON bodies and returns anything else as an excerpt.
+func pretty(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if len(body) <= maxHTMLBody && json.Valid(body) && json.Indent(&buf, body, "", "  ") == nil {
+		return buf.String()
+	}
+	return excerpt(body, maxHTMLBody)
+}
+
+// phases splits the request's bar into DNS, connect, TLS, server wait and
+// download, scaled against the slowest request of the run.
+func phases(req *htmlRequest, longest time.Duration) []htmlPhase {
+	t := req.Timing
+	if t == nil {
+		return []htmlPhase{{Name: "total", Duration: req.Duration, Percent: fmt.Sprintf("%.1f", percent(req.Duration, longest))}}
+	}
+	wait := t.TTFB - t.DNS - t.Connect - t.TLS
+	var out []htmlPhase
+	for _, p := range []htmlPhase{
+		{Name: "dns", Duration: t.DNS},
+		{Name: "connect", Duration: t.Connect},
+		{Name: "tls", Duration: t.TLS},
+		{Name: "wait", Duration: max(wait, 0)},
+		{Name: "download", Duration: max(t.Total-t.TTFB, 0)},
+	} {
+		if p.Duration > 0 {
+			p.Percent = fmt.Sprintf("%.1f", percent(p.Duration, longest))
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func percent(d, longest time.Duration) float64 {
+	if longest <= 0 {
+		return 0
+	}
+	return float64(d) / float64(longest) * 100
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+// maxExcerpt caps the bodies embedded in JSON records.
+const maxExcerpt = 4 << 10
+
+// record is the JSON form of a runner.Result.
+type record struct {
+	Time       time.Time      `json:"time"`
+	Suite      string         `json:"suite"`
+	Request    string         `json:"request"`
+	Test       string         `json:"test"`
+	Passed     bool           `json:"passed"`
+	Status     string         `json:"status"` // passed | failed | skipped
+	Error      string         `json:"error,omitempty"`
+	Kwargs     map[string]any `json:"kwargs,omitempty"`
+	DurationMS float64        `json:"duration_ms"`
+	Attempts   int            `json:"attempts,omitempty"`
+	Timing     *timingRecord  `json:"timing,omitempty"`
+	HTTPReq    *httpRecord    `json:"http_request,omitempty"`
+	HTTPResp   *httpRecord    `json:"http_response,omitempty"`
+}
+
+type timingRecord struct {
+	DNS     float64 `json:"dns_ms"`
+	Connect float64 `json:"connect_ms"`
+	TLS     float64 `json:"tls_ms"`
+	TTFB    float64 `json:"ttfb_ms"`
+	Total   float64 `json:"total_ms"`
+}
+
+type httpRecord struct {
+	Method   string      `json:"method,omitempty"`
+	URL      string      `json:"url,omitempty"`
+	Status   int         `json:"status,omitempty"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Body     string      `json:"body,omitempty"`
+	BodySize int         `json:"body_size"`
+}
+
+func newRecord(r runner.Result) record {
+	rec := record{
+		Time:       time.Now(),
+		Suite:      r.Suite,
+		Request:    r.Request,
+		Test:       r.TestName,
+		Passed:     r.Status == runner.StatusPassed,
+		Status:     r.Status.String(),
+		Kwargs:     r.Kwargs,
+		DurationMS: millis(r.Duration),
+		Attempts:   r.Attempts,
+	}
+	if t := r.Timing; t != nil {
+		rec.Timing = &timingRecord{
+			DNS:     millis(t.DNS),
+			Connect: millis(t.Connect),
+			TLS:     millis(t.TLS),
+			TTFB:    millis(t.TTFB),
+			Total:   millis(t.Total),
+		}
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	}
+	if s := r.Sent; s != nil {
+		rec.HTTPReq = &httpRecord{
+			Method:   s.Method,
+			URL:      s.URL,
+			Headers:  s.Headers,
+			Body:     excerpt(s.Body, maxExcerpt),
+			BodySize: len(s.Body),
+		}
+	}
+	if rc := r.Received; rc != nil {
+		rec.HTTPResp = &httpRecord{
+			Status:   rc.Status,
+			Headers:  rc.Headers,
+			Body:     excerpt(rc.Body, maxExcerpt),
+			BodySize: len(rc.Body),
+		}
+	}
+	return rec
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// jsonWriter streams records to a file, either as one JSON array or as
+// newline-delimited JSON. Each record is flushed as soon as it is written,
+// so the file can be tailed while the run is in progress.
+type jsonWriter struct {
+	f     *os.File
+	w     *bufio.Writer
+	lines bool
+	n     int
+}
+
+func newJSON(path string) (Writer, error)   { return openJSON(path, false) }
+func newNDJSON(path string) (Writer, error) { return openJSON(path, true) }
+
+func openJSON(path string, lines bool) (Writer, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	j := &jsonWriter{f: f, w: bufio.NewWriter(f), lines: lines}
+	if !lines {
+		j.w.WriteString("[")
+	}
+	return j, nil
+}
+
+func (j *jsonWriter) Write(r runner.Result) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(newRecord(r)); err != nil {
+		return fmt.Errorf("%s/%s: %w", r.Suite, r.Request, err)
+	}
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	switch {
+	case j.lines:
+	case j.n == 0:
+		j.w.WriteString("\n  ")
+	default:
+		j.w.WriteString(",\n  ")
+	}
+	j.n++
+	j.w.Write(data)
+	if j.lines {
+		j.w.WriteString("\n")
+	}
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.lines {
+		j.w.WriteString("\n]\n")
+	}
+	if err := j.w.Flush(); err != nil {
+		j.f.Close()
+		return err
+	}
+	return j.f.Close()
+}
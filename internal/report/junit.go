@@ -0,0 +1,164 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+
+	elapsed  time.Duration
+	requests map[*runner.RequestInfo]bool
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// junit collects results and writes a JUnit XML file on Close. Suites map to
+// <testsuite>, each result to a <testcase> named "request / test".
+type junit struct {
+	f      *os.File
+	start  time.Time
+	suites []*junitSuite
+	index  map[string]*junitSuite
+}
+
+func newJUnit(path string) (Writer, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &junit{f: f, start: time.Now(), index: map[string]*junitSuite{}}, nil
+}
+
+func (j *junit) Write(r runner.Result) error {
+	s, ok := j.index[r.Suite]
+	if !ok {
+		s = &junitSuite{
+			Name:      r.Suite,
+			Timestamp: time.Now().Format("2006-01-02T15:04:05"),
+			requests:  map[*runner.RequestInfo]bool{},
+		}
+		j.index[r.Suite] = s
+		j.suites = append(j.suites, s)
+	}
+
+	// Every expectation of a request carries the request's duration; the
+	// suite time counts each request once.
+	if r.Sent != nil && !s.requests[r.Sent] {
+		s.requests[r.Sent] = true
+		s.elapsed += r.Duration
+	}
+
+	c := junitCase{
+		ClassName: r.Suite + "." + r.Request,
+		Name:      r.Request + " / " + r.TestName,
+		Time:      seconds(r.Duration),
+	}
+	if out := dump(r); out != "" {
+		c.SystemOut = &junitText{Text: out}
+	}
+	s.Tests++
+	switch r.Status {
+	case runner.StatusSkipped:
+		c.Skipped = &junitSkipped{}
+		if r.Err != nil {
+			c.Skipped.Message = helpers.Sanitize(r.Err.Error())
+		}
+		s.Skipped++
+	case runner.StatusFailed:
+		msg := "failed"
+		if r.Err != nil {
+			msg = helpers.Sanitize(r.Err.Error())
+		}
+		p := &junitProblem{Message: msg, Type: r.TestName, Text: msg}
+		// A missing response means the check never ran: that's an error,
+		// not a failed assertion.
+		if r.Received == nil {
+			c.Error = p
+			s.Errors++
+		} else {
+			c.Failure = p
+			s.Failures++
+		}
+	}
+	s.Cases = append(s.Cases, c)
+	return nil
+}
+
+func (j *junit) Close() error {
+	doc := junitSuites{Name: "tapir", Time: seconds(time.Since(j.start))}
+	for _, s := range j.suites {
+		s.Time = seconds(s.elapsed)
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Skipped += s.Skipped
+		doc.Suites = append(doc.Suites, *s)
+	}
+
+	if _, err := j.f.WriteString(xml.Header); err != nil {
+		j.f.Close()
+		return err
+	}
+	enc := xml.NewEncoder(j.f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		j.f.Close()
+		return fmt.Errorf("junit report: %w", err)
+	}
+	if _, err := j.f.WriteString("\n"); err != nil {
+		j.f.Close()
+		return err
+	}
+	return j.f.Close()
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
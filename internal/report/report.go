@@ -0,0 +1,104 @@
+// Package report writes run results to files in machine-readable formats.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+// Writer receives every result of a run, in arrival order. Close is called
+// once the run is over and must flush the report to disk.
+type Writer interface {
+	Write(r runner.Result) error
+	Close() error
+}
+
+var formats = map[string]func(path string) (Writer, error){
+	"junit":  newJUnit,
+	"json":   newJSON,
+	"ndjson": newNDJSON,
+	"html":   newHTML,
+}
+
+// Spec is a parsed --report value of the form format=path.
+type Spec struct {
+	Format string
+	Path   string
+}
+
+// ParseSpec parses "format=path", e.g. "junit=out/results.xml".
+func ParseSpec(s string) (Spec, error) {
+	format, path, ok := strings.Cut(s, "=")
+	if !ok || path == "" {
+		return Spec{}, fmt.Errorf("invalid report %q, expected format=path", s)
+	}
+	if _, ok := formats[format]; !ok {
+		return Spec{}, fmt.Errorf("unknown report format %q (supported: %s)", format,
+			strings.Join(slices.Sorted(maps.Keys(formats)), ", "))
+	}
+	return Spec{Format: format, Path: path}, nil
+}
+
+// Open creates the report file for spec.
+func Open(spec Spec) (Writer, error) {
+	w, err := formats[spec.Format](spec.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s report: %w", spec.Format, err)
+	}
+	return w, nil
+}
+
+// WriteFile writes a complete report for results in one go.
+func WriteFile(spec Spec, results []runner.Result) error {
+	w, err := Open(spec)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := w.Write(r); err != nil {
+			w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// Tee forwards every result of in to the returned channel and to ws. When in
+// is drained the writers are closed before the returned channel is, so the
+// reports are complete once a reader sees the end of the run. The returned
+// function reports the first write or close error; call it after draining.
+func Tee(in <-chan runner.Result, ws []Writer) (<-chan runner.Result, func() error) {
+	if len(ws) == 0 {
+		return in, func() error { return nil }
+	}
+
+	out := make(chan runner.Result)
+	var errs []error
+	go func() {
+		defer close(out)
+		failed := make([]bool, len(ws))
+		for r := range in {
+			for i, w := range ws {
+				if failed[i] {
+					continue
+				}
+				if err := w.Write(r); err != nil {
+					errs = append(errs, err)
+					failed[i] = true
+				}
+			}
+			out <- r
+		}
+		for _, w := range ws {
+			if err := w.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}()
+	return out, func() error { return errors.Join(errs...) }
+}
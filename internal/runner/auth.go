@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/httpclient"
+	"github.com/IsmailCLN/tapir/internal/sharedcontext"
+	"github.com/IsmailCLN/tapir/internal/templating"
+)
+
+// tokenExpiryDelta refreshes OAuth2 tokens this long before they expire, so
+// a token does not run out while a request is in flight.
+const tokenExpiryDelta = 10 * time.Second
+
+// TokenPlaceholder stands in for OAuth2 tokens under WithoutTokenFetch.
+const TokenPlaceholder = "<oauth2-token>"
+
+type noTokenFetchKey struct{}
+
+// WithoutTokenFetch returns a context under which BuildRequest does not
+// contact OAuth2 token endpoints and sets TokenPlaceholder instead, for
+// printing requests without sending anything.
+func WithoutTokenFetch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTokenFetchKey{}, true)
+}
+
+// effectiveAuth returns the request's auth, else the suite's, or nil when
+// none applies.
+func effectiveAuth(s *domain.TestSuite, r domain.HTTPRequest) *domain.Auth {
+	a := r.Auth
+	if a == nil {
+		a = s.Auth
+	}
+	if a == nil || a.Type == domain.AuthNone {
+		return nil
+	}
+	return a
+}
+
+// applyAuth sets the credentials of a on req. Every credential, and the
+// header value built from it, is registered as a secret so it never shows up
+// in results or reports.
+func applyAuth(ctx context.Context, req *http.Request, a *domain.Auth, client httpclient.Options, timeout time.Duration, vars *sharedcontext.SharedContext) error {
+	if a == nil {
+		return nil
+	}
+	expand := func(s string) string {
+		v := templating.Expand(s, vars)
+		vars.Secrets().Add(v)
+		return v
+	}
+
+	switch a.Type {
+	case domain.AuthBasic:
+		req.SetBasicAuth(templating.Expand(a.Username, vars), expand(a.Password))
+	case domain.AuthBearer:
+		token := expand(a.Token)
+		if token == "" {
+			return fmt.Errorf("bearer: empty token")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case domain.AuthAPIKey:
+		name, value := templating.Expand(a.Name, vars), expand(a.Value)
+		if name == "" {
+			return fmt.Errorf("api_key: name is required")
+		}
+		switch a.In {
+		case "", "header":
+			req.Header.Set(name, value)
+		case "query":
+			// append, so the query written in the suite is sent as-is
+			param := url.QueryEscape(name) + "=" + url.QueryEscape(value)
+			if req.URL.RawQuery != "" {
+				param = req.URL.RawQuery + "&" + param
+			}
+			req.URL.RawQuery = param
+		default:
+			return fmt.Errorf("api_key: in must be header or query, got %q", a.In)
+		}
+	case domain.AuthOAuth2Credentials:
+		if ctx.Value(noTokenFetchKey{}) != nil {
+			req.Header.Set("Authorization", "Bearer "+TokenPlaceholder)
+			return nil
+		}
+		token, err := oauth2Token(ctx, a, client, timeout, vars, expand)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return fmt.Errorf("unknown type %q", a.Type)
+	}
+
+	vars.Secrets().Add(req.Header.Get("Authorization"))
+	return nil
+}
+
+// oauth2Token returns an access token for the client credentials grant of a,
+// fetching a new one when the cached token is missing or about to expire.
+// The token endpoint is reached with the client settings of the request.
+func oauth2Token(ctx context.Context, a *domain.Auth, client httpclient.Options, timeout time.Duration, vars *sharedcontext.SharedContext, expand func(string) string) (string, error) {
+	tokenURL := templating.Expand(a.TokenURL, vars)
+	clientID := templating.Expand(a.ClientID, vars)
+	secret := expand(a.ClientSecret)
+	if tokenURL == "" || clientID == "" {
+		return "", fmt.Errorf("oauth2_client_credentials: token_url and client_id are required")
+	}
+	scope := templating.Expand(strings.Join(a.Scopes, " "), vars)
+
+	key := strings.Join([]string{tokenURL, clientID, scope}, "\x00")
+	return vars.Token(key, func() (string, time.Time, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if scope != "" {
+			form.Set("scope", scope)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "application/json")
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(secret))
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		resp, _, err := httpclient.Do(ctx, req, client)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("token request: %w", err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("token request: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", time.Time{}, fmt.Errorf("token request: %s: %.200s", resp.Status, data)
+		}
+
+		var tok struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := json.Unmarshal(data, &tok); err != nil {
+			return "", time.Time{}, fmt.Errorf("token response: %w", err)
+		}
+		if tok.AccessToken == "" {
+			return "", time.Time{}, fmt.Errorf("token response has no access_token")
+		}
+		vars.Secrets().Add(tok.AccessToken)
+
+		var expiry time.Time
+		if tok.ExpiresIn > 0 {
+			expiry = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - tokenExpiryDelta)
+		}
+		return tok.AccessToken, expiry, nil
+	})
+}
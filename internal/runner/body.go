@@ -0,0 +1,182 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/templating"
+)
+
+// buildBody encodes the payload of r and returns it together with the
+// Content-Type it implies. An empty content type means "leave it to the user".
+// Relative file paths are resolved against baseDir.
+func buildBody(r domain.HTTPRequest, baseDir string, vars templating.Vars) (io.Reader, string, error) {
+	set := 0
+	for _, present := range []bool{r.Body != nil, len(r.Form) > 0, len(r.Multipart) > 0, r.BodyFile != ""} {
+		if present {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, "", errors.New("only one of body, form, multipart and body_file may be set")
+	}
+
+	switch {
+	case r.Body != nil:
+		return encodeBody(r.Body, vars)
+	case len(r.Form) > 0:
+		return encodeForm(r.Form, vars)
+	case len(r.Multipart) > 0:
+		return encodeMultipart(r.Multipart, baseDir, vars)
+	case r.BodyFile != "":
+		path := resolvePath(baseDir, templating.Expand(r.BodyFile, vars))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("body_file: %w", err)
+		}
+		return bytes.NewReader(data), mime.TypeByExtension(filepath.Ext(path)), nil
+	}
+	return nil, "", nil
+}
+
+// encodeBody sends strings verbatim and everything else (maps, lists, numbers…) as JSON.
+func encodeBody(body any, vars templating.Vars) (io.Reader, string, error) {
+	if s, ok := body.(string); ok {
+		if s == "" {
+			return nil, "", nil
+		}
+		return strings.NewReader(templating.Expand(s, vars)), "", nil
+	}
+
+	data, err := json.Marshal(jsonCompatible(templating.ExpandAny(body, vars)))
+	if err != nil {
+		return nil, "", fmt.Errorf("body: cannot encode as JSON: %w", err)
+	}
+	return bytes.NewReader(data), "application/json", nil
+}
+
+func encodeForm(form map[string]any, vars templating.Vars) (io.Reader, string, error) {
+	values := url.Values{}
+	for _, k := range slices.Sorted(maps.Keys(form)) {
+		switch v := form[k].(type) {
+		case []any:
+			for _, e := range v {
+				s, err := helpers.AsString(e)
+				if err != nil {
+					return nil, "", fmt.Errorf("form field %s: %w", k, err)
+				}
+				values.Add(k, templating.Expand(s, vars))
+			}
+		case nil:
+			values.Add(k, "")
+		default:
+			s, err := helpers.AsString(v)
+			if err != nil {
+				return nil, "", fmt.Errorf("form field %s: %w", k, err)
+			}
+			values.Add(k, templating.Expand(s, vars))
+		}
+	}
+	return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
+}
+
+func encodeMultipart(parts []domain.MultipartPart, baseDir string, vars templating.Vars) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, p := range parts {
+		if p.Name == "" {
+			return nil, "", errors.New("multipart: every part needs a name")
+		}
+		if p.File == "" {
+			if err := w.WriteField(p.Name, templating.Expand(p.Value, vars)); err != nil {
+				return nil, "", fmt.Errorf("multipart field %s: %w", p.Name, err)
+			}
+			continue
+		}
+
+		path := resolvePath(baseDir, templating.Expand(p.File, vars))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart field %s: %w", p.Name, err)
+		}
+		filename := p.Filename
+		if filename == "" {
+			filename = filepath.Base(path)
+		}
+		ctype := p.ContentType
+		if ctype == "" {
+			ctype = mime.TypeByExtension(filepath.Ext(path))
+		}
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     p.Name,
+			"filename": filename,
+		}))
+		h.Set("Content-Type", ctype)
+		pw, err := w.CreatePart(h)
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart field %s: %w", p.Name, err)
+		}
+		if _, err := pw.Write(data); err != nil {
+			return nil, "", fmt.Errorf("multipart field %s: %w", p.Name, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("multipart: %w", err)
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+// jsonCompatible converts map[any]any (which encoding/json rejects) into
+// map[string]any, recursively.
+func jsonCompatible(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, vv := range t {
+			out[fmt.Sprint(k)] = jsonCompatible(vv)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, vv := range t {
+			out[k] = jsonCompatible(vv)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, vv := range t {
+			out[i] = jsonCompatible(vv)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func resolvePath(baseDir, p string) string {
+	if filepath.IsAbs(p) || baseDir == "" {
+		return p
+	}
+	return filepath.Join(baseDir, p)
+}
@@ -3,17 +3,14 @@ package runner
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"runtime"
 	"strings"
 	"sync"
 
-	"maps"
-
 	"github.com/IsmailCLN/tapir/internal/assert"
 	"github.com/IsmailCLN/tapir/internal/domain"
-	"github.com/IsmailCLN/tapir/internal/httpclient"
+	"github.com/IsmailCLN/tapir/internal/secrets"
 	"github.com/IsmailCLN/tapir/internal/sharedcontext"
 )
 
@@ -22,28 +19,73 @@ type Options struct {
 	// Concurrency is the number of worker goroutines.
 	// If <= 0, runtime.NumCPU() is used.
 	Concurrency int
+
+	// Vars seeds the shared context, e.g. from an environment profile and
+	// --var flags. Captures made during the run take precedence.
+	Vars map[string]string
+
+	// Secrets resolves ${env:NAME} placeholders. Every value it hands out is
+	// masked in the results. If nil, only the process environment is used.
+	Secrets *secrets.Store
+
+	// Client holds the run-wide HTTP client settings. Suites and requests
+	// override them key by key.
+	Client domain.ClientOptions
+
+	// Inspect, if set, is called with every exchange that produced a
+	// response. The results it returns are reported with the request's own.
+	Inspect func(ex Exchange) []Result
+}
+
+// Exchange is a completed request/response pair handed to Options.Inspect.
+type Exchange struct {
+	Suite    string
+	Request  string
+	HTTPReq  *http.Request
+	HTTPResp *http.Response
+	Body     []byte
 }
 
 // RunConcurrent executes all requests across the given suites in parallel,
 // but respects per-suite dependencies declared via TestRequest.DependsOn.
+// A request whose dependencies did not all pass is reported as skipped
+// ("blocked by …") unless it sets run_always.
 // Each expectation result is streamed on the returned channel as soon as evaluated.
 func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options) <-chan Result {
 	out := make(chan Result)
 
 	shared := sharedcontext.New()
+	shared.SetAll(opts.Vars)
+	if opts.Secrets != nil {
+		shared.UseSecrets(opts.Secrets)
+	}
 	assert.SetSharedContext(shared)
 
+	for i := range suites {
+		suites[i].ClientOptions = suites[i].ClientOptions.Or(opts.Client)
+	}
+	focus := focused(suites)
+
 	type job struct {
-		SuiteName string
+		Suite     *domain.TestSuite
 		Req       domain.TestRequest
+		BlockedBy []string // dependencies that failed or were skipped
 	}
 	type done struct {
 		SuiteName string
 		ReqName   string
+		Passed    bool
+	}
+
+	total := 0
+	for _, s := range suites {
+		total += len(s.Requests)
 	}
 
 	jobs := make(chan job)
-	doneCh := make(chan done)
+	// Buffered so a finishing worker never waits on the scheduler while the
+	// scheduler waits on a free worker.
+	doneCh := make(chan done, total)
 
 	// workers
 	n := opts.Concurrency
@@ -62,7 +104,7 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 					return
 				default:
 				}
-				results := runRequest(ctx, jb.SuiteName, jb.Req, shared)
+				results := execute(ctx, jb.Suite, jb.Req, shared, opts, focus, jb.BlockedBy)
 				for _, r := range results {
 					select {
 					case out <- r:
@@ -72,7 +114,7 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 				}
 				// notify scheduler this request is finished
 				select {
-				case doneCh <- done{SuiteName: jb.SuiteName, ReqName: jb.Req.Name}:
+				case doneCh <- done{SuiteName: jb.Suite.Name, ReqName: jb.Req.Name, Passed: allPassed(results)}:
 				case <-ctx.Done():
 					return
 				}
@@ -86,21 +128,27 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 
 		// Build graphs
 		type graph struct {
+			suite    *domain.TestSuite
 			indeg    map[string]int
 			children map[string][]string
 			reqs     map[string]domain.TestRequest
-			total    int
-			sent     int
-			done     int
+			blocked  map[string][]string // request -> dependencies that did not pass
+			sent     map[string]bool
+			finished map[string]bool
 		}
 
 		graphs := make(map[string]*graph)
 
-		for _, s := range suites {
+		for i := range suites {
+			s := &suites[i]
 			g := &graph{
+				suite:    s,
 				indeg:    make(map[string]int),
 				children: make(map[string][]string),
 				reqs:     make(map[string]domain.TestRequest),
+				blocked:  make(map[string][]string),
+				sent:     make(map[string]bool),
+				finished: make(map[string]bool),
 			}
 			for _, r := range s.Requests {
 				g.reqs[r.Name] = r
@@ -118,7 +166,7 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 						case out <- Result{
 							Suite:    s.Name,
 							Request:  r.Name,
-							Passed:   false,
+							Status:   StatusFailed,
 							Err:      fmt.Errorf("depends_on references unknown request %q", dep),
 							TestName: "depends_on",
 						}:
@@ -132,19 +180,18 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 					g.children[dep] = append(g.children[dep], r.Name)
 				}
 			}
-			g.total = len(g.reqs)
 			graphs[s.Name] = g
 		}
 
 		// queue initial ready jobs
-		var activeSuites int
-		for sName, g := range graphs {
+		inFlight := 0
+		for _, g := range graphs {
 			for name, deg := range g.indeg {
 				if deg == 0 {
 					select {
-					case jobs <- job{SuiteName: sName, Req: g.reqs[name]}:
-						g.sent++
-						activeSuites++
+					case jobs <- job{Suite: g.suite, Req: g.reqs[name]}:
+						g.sent[name] = true
+						inFlight++
 					case <-ctx.Done():
 						return
 					}
@@ -152,49 +199,61 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 			}
 		}
 
-		// react to completions and release dependents
-		totalSent := 0
-		for _, g := range graphs {
-			totalSent += g.sent
-		}
-
-		for {
-			if totalSent == 0 {
-				// nothing sent initially (e.g., cycles or empty), break
-				break
-			}
+		// react to completions and release dependents until every request
+		// has finished or nothing is left in flight
+		for inFlight > 0 {
 			select {
 			case d := <-doneCh:
-				// mark done
+				inFlight--
 				g := graphs[d.SuiteName]
-				g.done++
+				g.finished[d.ReqName] = true
 				// release children
 				for _, child := range g.children[d.ReqName] {
+					if !d.Passed {
+						g.blocked[child] = append(g.blocked[child], d.ReqName)
+					}
 					g.indeg[child]--
 					if g.indeg[child] == 0 {
 						select {
-						case jobs <- job{SuiteName: d.SuiteName, Req: g.reqs[child]}:
-							g.sent++
-							totalSent++
+						case jobs <- job{Suite: g.suite, Req: g.reqs[child], BlockedBy: g.blocked[child]}:
+							g.sent[child] = true
+							inFlight++
 						case <-ctx.Done():
 							return
 						}
 					}
 				}
-				// if finished all from this suite and all suites done, we may continue waiting others
-				// Stop when every suite has g.done == g.total
-				allDone := true
-				for _, gg := range graphs {
-					if gg.done < gg.total {
-						allDone = false
-						break
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// Whatever was never sent waits, directly or not, on a depends_on
+		// cycle. Report it rather than dropping it.
+		for _, s := range suites {
+			g := graphs[s.Name]
+			for _, r := range s.Requests {
+				if g.sent[r.Name] {
+					continue
+				}
+				var waiting []string
+				for _, dep := range r.DependsOn {
+					if _, ok := g.reqs[dep]; ok && !g.finished[dep] {
+						waiting = append(waiting, dep)
 					}
 				}
-				if allDone {
+				g.sent[r.Name] = true
+				select {
+				case out <- Result{
+					Suite:    s.Name,
+					Request:  r.Name,
+					Status:   StatusFailed,
+					Err:      fmt.Errorf("not run: depends_on cycle (waiting on %s)", strings.Join(waiting, ", ")),
+					TestName: "depends_on",
+				}:
+				case <-ctx.Done():
 					return
 				}
-			case <-ctx.Done():
-				return
 			}
 		}
 	}()
@@ -207,78 +266,3 @@ func RunConcurrent(ctx context.Context, suites []domain.TestSuite, opts Options)
 
 	return out
 }
-
-// runRequest executes a single request and returns one Result per expectation.
-func runRequest(ctx context.Context, suite string, r domain.TestRequest, shared *sharedcontext.SharedContext) []Result {
-	var results []Result
-
-	// ----- 1. Build request body (string only for now) -----
-	var bodyReader io.Reader
-	if bodyStr, ok := r.Req.Body.(string); ok && bodyStr != "" {
-		bodyReader = strings.NewReader(bodyStr)
-	}
-
-	// ----- 2. Construct HTTP request -----
-	req, err := http.NewRequest(r.Req.Method, r.Req.URL, bodyReader)
-	if err != nil {
-		appendRequestErrorResults(&results, suite, r, err)
-		return results
-	}
-
-	// ----- 3. Apply headers with placeholder substitution -----
-	for k, v := range r.Req.Headers {
-		if strings.Contains(v, "${token}") {
-			if t, ok := shared.Get("token"); ok {
-				v = strings.ReplaceAll(v, "${token}", t)
-			}
-		}
-		req.Header.Set(k, v)
-	}
-
-	// ----- 4. Send request -----
-	resp, err := httpclient.Do(ctx, req)
-	if err != nil {
-		appendRequestErrorResults(&results, suite, r, err)
-		return results
-	}
-	bodyBytes, err := io.ReadAll(resp.Body)
-	resp.Body.Close()
-	if err != nil {
-		appendRequestErrorResults(&results, suite, r, err)
-		return results
-	}
-
-	// ----- 5. Evaluate expectations -----
-	for _, exp := range r.Expect {
-		// 5a. Copy user‑provided kwargs
-		kwargs := make(map[string]any, len(exp.Kwargs)+2)
-		maps.Copy(kwargs, exp.Kwargs)
-
-		// 5b. Inject auto params
-		kwargs["status_code"] = resp.StatusCode
-		kwargs["headers"] = resp.Header
-
-		f, ok := assert.Get(exp.Type)
-		if !ok {
-			results = append(results, Result{
-				Suite:    suite,
-				Request:  r.Name,
-				Passed:   false,
-				Err:      fmt.Errorf("unknown expectation %s", exp.Type),
-				TestName: exp.Type,
-			})
-			continue
-		}
-
-		err := f(bodyBytes, kwargs)
-		results = append(results, Result{
-			Suite:    suite,
-			Request:  r.Name,
-			Passed:   err == nil,
-			Err:      err,
-			TestName: exp.Type,
-		})
-	}
-
-	return results
-}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/httpclient"
+	"github.com/IsmailCLN/tapir/internal/sharedcontext"
+	"github.com/IsmailCLN/tapir/internal/templating"
+)
+
+// resolveURL expands raw and, when it has no scheme, appends it to the
+// suite's base_url or, failing that, to the base_url variable (e.g. from an
+// environment profile). The base path is kept: "/users" against
+// "https://api/v1" gives "https://api/v1/users".
+func resolveURL(s *domain.TestSuite, raw string, vars *sharedcontext.SharedContext) string {
+	u := templating.Expand(raw, vars)
+	if isAbsolute(u) {
+		return u
+	}
+
+	base := templating.Expand(s.BaseURL, vars)
+	if base == "" {
+		base, _ = vars.Lookup("base_url")
+	}
+	if base == "" {
+		return u
+	}
+	if u == "" || strings.HasPrefix(u, "?") {
+		return base + u
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(u, "/")
+}
+
+func isAbsolute(u string) bool {
+	p, err := url.Parse(u)
+	return err == nil && p.Scheme != "" && p.Host != ""
+}
+
+// clientOptions merges the client settings of r over the suite's (which
+// already carry the run-wide defaults) and returns them together with the
+// request timeout.
+func clientOptions(s *domain.TestSuite, r domain.HTTPRequest) (httpclient.Options, time.Duration) {
+	o := r.ClientOptions.Or(s.ClientOptions)
+
+	timeout := time.Duration(o.Timeout)
+	if timeout <= 0 {
+		timeout = httpclient.DefaultTimeout
+	}
+	path := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(suiteDir(s), p)
+	}
+	return httpclient.Options{
+		NoRedirects:  o.FollowRedirects != nil && !*o.FollowRedirects,
+		MaxRedirects: o.MaxRedirects,
+		Transport: httpclient.Transport{
+			Proxy:              o.Proxy,
+			InsecureSkipVerify: o.InsecureSkipVerify != nil && *o.InsecureSkipVerify,
+			CAFile:             path(o.CAFile),
+			CertFile:           path(o.CertFile),
+			KeyFile:            path(o.KeyFile),
+			DisableHTTP2:       o.HTTP2 != nil && !*o.HTTP2,
+		},
+	}, timeout
+}
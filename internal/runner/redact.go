@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IsmailCLN/tapir/internal/curl"
+	"github.com/IsmailCLN/tapir/internal/secrets"
+)
+
+// redact masks secret values in everything results carry to the user:
+// errors, kwargs and the request/response dumps. Results of one request
+// keep sharing their RequestInfo and ResponseInfo.
+func redact(results []Result, store *secrets.Store) []Result {
+	if store.Empty() {
+		return results
+	}
+
+	sent := map[*RequestInfo]*RequestInfo{}
+	received := map[*ResponseInfo]*ResponseInfo{}
+	for i := range results {
+		r := &results[i]
+		if r.Err != nil {
+			if msg := store.Redact(r.Err.Error()); msg != r.Err.Error() {
+				r.Err = errors.New(msg)
+			}
+		}
+		if r.Kwargs != nil {
+			r.Kwargs = redactAny(r.Kwargs, store).(map[string]any)
+		}
+		if s := r.Sent; s != nil {
+			if _, ok := sent[s]; !ok {
+				sent[s] = &RequestInfo{
+					Method:  s.Method,
+					URL:     store.Redact(s.URL),
+					Headers: redactHeader(s.Headers, store),
+					Body:    []byte(store.Redact(string(s.Body))),
+					Curl:    store.Redact(curl.FormatEnv(s.Method, s.URL, s.Headers, s.Body, store.Names())),
+				}
+			}
+			r.Sent = sent[s]
+		}
+		if rc := r.Received; rc != nil {
+			if _, ok := received[rc]; !ok {
+				received[rc] = &ResponseInfo{
+					Status:  rc.Status,
+					Headers: redactHeader(rc.Headers, store),
+					Body:    []byte(store.Redact(string(rc.Body))),
+				}
+			}
+			r.Received = received[rc]
+		}
+	}
+	return results
+}
+
+func redactHeader(h http.Header, store *secrets.Store) http.Header {
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		for _, v := range vs {
+			out[k] = append(out[k], store.Redact(v))
+		}
+	}
+	return out
+}
+
+func redactAny(v any, store *secrets.Store) any {
+	switch t := v.(type) {
+	case string:
+		return store.Redact(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, vv := range t {
+			out[k] = redactAny(vv, store)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, vv := range t {
+			out[i] = redactAny(vv, store)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+)
+
+const (
+	defaultAttempts      = 3
+	defaultRetryInterval = time.Second
+	// maxRetryInterval caps exponential backoff without a max_interval.
+	maxRetryInterval = 5 * time.Minute
+)
+
+// retryDelay returns how long to wait after the given attempt (1-based)
+// before sending the next one.
+func retryDelay(rt *domain.Retry, attempt int) time.Duration {
+	d := time.Duration(rt.Interval)
+	if d <= 0 {
+		d = defaultRetryInterval
+	}
+	if rt.Backoff == domain.BackoffExponential {
+		limit := time.Duration(rt.MaxInterval)
+		if limit <= 0 {
+			limit = maxRetryInterval
+		}
+		for i := 1; i < attempt && d < limit; i++ {
+			d *= 2
+		}
+		d = min(d, limit)
+	}
+	if rt.Jitter > 0 {
+		d += time.Duration(float64(d) * rt.Jitter * (2*rand.Float64() - 1))
+	}
+	return max(d, 0)
+}
+
+// wait sleeps for d or until ctx is done, whichever comes first.
+func wait(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,27 +1,95 @@
 package runner
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"net/http"
-	"strings"
-
 	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/IsmailCLN/tapir/internal/assert"
+	"github.com/IsmailCLN/tapir/internal/capture"
 	"github.com/IsmailCLN/tapir/internal/domain"
 	"github.com/IsmailCLN/tapir/internal/httpclient"
 	"github.com/IsmailCLN/tapir/internal/sharedcontext"
+	"github.com/IsmailCLN/tapir/internal/templating"
+)
+
+// Status is the outcome of a Result.
+type Status int
+
+const (
+	StatusPassed Status = iota
+	StatusFailed
+	StatusSkipped
 )
 
+func (s Status) String() string {
+	switch s {
+	case StatusPassed:
+		return "passed"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// statusOf maps an expectation error to a Status.
+func statusOf(err error) Status {
+	if err != nil {
+		return StatusFailed
+	}
+	return StatusPassed
+}
+
 // Result holds the outcome of a single request-level expectation.
 type Result struct {
-	Suite    string
-	Request  string
-	Passed   bool
+	Suite   string
+	Request string
+	Status  Status
+	// Err explains a failure; for skipped results, why the request was skipped.
 	Err      error
 	TestName string
+
+	// Sent is the request as sent, after template expansion. It is nil when
+	// the request could not be built.
+	Sent *RequestInfo
+	// Received is the response, or nil when none arrived.
+	Received *ResponseInfo
+	// Duration is the time from sending the request to reading the whole body.
+	Duration time.Duration
+	// Timing breaks Duration down into phases. It is nil when nothing was sent.
+	Timing *httpclient.Timing
+	// Kwargs are the expectation's own kwargs after template expansion,
+	// without the ones injected by the runner.
+	Kwargs map[string]any
+	// Attempts is how often the request was sent; above 1 only with retry.
+	Attempts int
+}
+
+// RequestInfo is a resolved request, shared by all results of that request.
+type RequestInfo struct {
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    []byte
+
+	// Curl is the request as a curl command, set when secrets were masked.
+	// Values read with ${env:NAME} appear as "${NAME}" so it still runs.
+	Curl string
+}
+
+// ResponseInfo is a received response, shared by all results of that request.
+type ResponseInfo struct {
+	Status  int
+	Headers http.Header
+	Body    []byte
 }
 
 func Run(ctx context.Context, suites []domain.TestSuite) ([]Result, error) {
@@ -30,81 +98,250 @@ func Run(ctx context.Context, suites []domain.TestSuite) ([]Result, error) {
 	shared := sharedcontext.New()
 	assert.SetSharedContext(shared)
 
-	for _, s := range suites {
-		for _, r := range s.Requests {
-
-			// ----- 1. Build request body (string only for now) -----
-			var bodyReader io.Reader
-			if bodyStr, ok := r.Req.Body.(string); ok && bodyStr != "" {
-				bodyReader = strings.NewReader(bodyStr)
+	focus := focused(suites)
+	for i := range suites {
+		// Requests run in file order; a dependency declared later never
+		// blocks.
+		failed := map[string]bool{}
+		for _, r := range suites[i].Requests {
+			var blockedBy []string
+			for _, dep := range r.DependsOn {
+				if failed[dep] {
+					blockedBy = append(blockedBy, dep)
+				}
 			}
+			res := execute(ctx, &suites[i], r, shared, Options{}, focus, blockedBy)
+			failed[r.Name] = !allPassed(res)
+			results = append(results, res...)
+		}
+	}
 
-			// ----- 2. Construct HTTP request -----
-			req, err := http.NewRequest(r.Req.Method, r.Req.URL, bodyReader)
-			if err != nil {
-				appendRequestErrorResults(&results, s.Name, r, err)
-				continue
-			}
+	return results, nil
+}
 
-			// ----- 3. Apply headers with placeholder substitution -----
-			for k, v := range r.Req.Headers {
-				if strings.Contains(v, "${token}") {
-					if t, ok := shared.Get("token"); ok {
-						v = strings.ReplaceAll(v, "${token}", t)
-					}
-				}
-				req.Header.Set(k, v)
-			}
+// BuildRequest expands the ${var} placeholders of r against vars and returns
+// the HTTP request that would be sent, together with its encoded body. Suite
+// defaults (base_url, headers, auth) are applied unless r overrides them.
+// OAuth2 tokens are fetched, or taken from the cache in vars, with ctx.
+func BuildRequest(ctx context.Context, s *domain.TestSuite, r domain.TestRequest, vars *sharedcontext.SharedContext) (*http.Request, []byte, error) {
+	// ----- 1. Build request body -----
+	bodyReader, contentType, err := buildBody(r.Req, suiteDir(s), vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	var body []byte
+	if bodyReader != nil {
+		if body, err = io.ReadAll(bodyReader); err != nil {
+			return nil, nil, err
+		}
+	}
 
-			// ----- 4. Send request -----
-			resp, err := httpclient.Do(ctx, req)
-			if err != nil {
-				appendRequestErrorResults(&results, s.Name, r, err)
-				continue
-			}
+	// ----- 2. Construct HTTP request -----
+	req, err := http.NewRequestWithContext(ctx, r.Req.Method, resolveURL(s, r.Req.URL, vars), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if body == nil {
+		req.Body, req.ContentLength = http.NoBody, 0
+	}
 
-			bodyBytes, err := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			if err != nil {
-				appendRequestErrorResults(&results, s.Name, r, err)
-				continue
-			}
+	// ----- 3. Apply headers with placeholder substitution -----
+	// Suite headers, then auth, then the request's own headers.
+	for k, v := range s.Headers {
+		req.Header.Set(k, templating.Expand(v, vars))
+	}
+	client, timeout := clientOptions(s, r.Req)
+	if err := applyAuth(ctx, req, effectiveAuth(s, r.Req), client, timeout, vars); err != nil {
+		return nil, nil, fmt.Errorf("auth: %w", err)
+	}
+	for k, v := range r.Req.Headers {
+		req.Header.Set(k, templating.Expand(v, vars))
+	}
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, body, nil
+}
 
-			// ----- 5. Evaluate expectations -----
-			for _, exp := range r.Expect {
-				// 5a. Copy user‑provided kwargs
-				kwargs := make(map[string]any, len(exp.Kwargs)+1)
-				maps.Copy(kwargs, exp.Kwargs)
-
-				// 5b. Inject auto params
-				kwargs["status_code"] = resp.StatusCode
-				kwargs["headers"] = resp.Header
-
-				f, ok := assert.Get(exp.Type)
-				if !ok {
-					results = append(results, Result{
-						Suite:    s.Name,
-						Request:  r.Name,
-						Passed:   false,
-						Err:      fmt.Errorf("unknown expectation %s", exp.Type),
-						TestName: exp.Type,
-					})
-					continue
-				}
+// runRequest executes a single request and returns one Result per expectation.
+// ${var} placeholders in the URL, headers, body and expectation kwargs are
+// expanded against the shared context right before the request is sent.
+// With a retry block the request is re-sent, and rebuilt, until its
+// conditions hold; the results of the last attempt are returned.
+func runRequest(ctx context.Context, s *domain.TestSuite, r domain.TestRequest, shared *sharedcontext.SharedContext, opts Options) []Result {
+	attempts := 1
+	if r.Retry != nil {
+		attempts = r.Retry.Attempts
+		if attempts <= 0 {
+			attempts = defaultAttempts
+		}
+	}
 
-				err := f(bodyBytes, kwargs)
-				results = append(results, Result{
-					Suite:    s.Name,
-					Request:  r.Name,
-					Passed:   err == nil,
-					Err:      err,
-					TestName: exp.Type,
-				})
+	for attempt := 1; ; attempt++ {
+		final := attempt >= attempts
+		results, settled := attemptRequest(ctx, s, r, shared, opts, final)
+		if settled || final || wait(ctx, retryDelay(r.Retry, attempt)) != nil {
+			for i := range results {
+				results[i].Attempts = attempt
 			}
+			return redact(results, shared.Secrets())
 		}
 	}
+}
 
-	return results, nil
+// attemptRequest builds and sends r once. settled reports whether the retry
+// conditions of r hold (always true without retry); results are only
+// inspected when settled or final.
+func attemptRequest(ctx context.Context, s *domain.TestSuite, r domain.TestRequest, shared *sharedcontext.SharedContext, opts Options, final bool) (results []Result, settled bool) {
+	req, sent, err := BuildRequest(ctx, s, r, shared)
+	if err != nil {
+		appendRequestErrorResults(&results, s.Name, r, err)
+		return results, true
+	}
+
+	info := &RequestInfo{Method: req.Method, URL: req.URL.String(), Headers: req.Header.Clone(), Body: sent}
+	results, received, timing, settled := sendRequest(ctx, s, r, req, shared, opts, final)
+	for i := range results {
+		results[i].Sent = info
+		results[i].Received = received
+		results[i].Duration = timing.Total
+		results[i].Timing = timing
+	}
+	return results, settled
+}
+
+// sendRequest sends req, captures values and evaluates the expectations of r.
+func sendRequest(ctx context.Context, s *domain.TestSuite, r domain.TestRequest, req *http.Request, shared *sharedcontext.SharedContext, opts Options, final bool) ([]Result, *ResponseInfo, *httpclient.Timing, bool) {
+	var results []Result
+	suite := s.Name
+
+	// ----- 4. Send request -----
+	client, timeout := clientOptions(s, r.Req)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, timing, err := httpclient.Do(ctx, req, client)
+	if err != nil {
+		appendRequestErrorResults(&results, suite, r, err)
+		return results, nil, timing, r.Retry == nil
+	}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	timing.Done()
+	if err != nil {
+		appendRequestErrorResults(&results, suite, r, err)
+		return results, nil, timing, r.Retry == nil
+	}
+	received := &ResponseInfo{Status: resp.StatusCode, Headers: resp.Header, Body: bodyBytes}
+
+	// ----- 5. Capture values for later requests -----
+	results = append(results, captureValues(suite, r, resp, bodyBytes, shared)...)
+
+	// ----- 6. Evaluate expectations -----
+	results = append(results, evaluate(s, r.Name, r.Expect, resp, bodyBytes, timing, shared)...)
+
+	settled := true
+	if r.Retry != nil {
+		conds := results
+		if len(r.Retry.Until) > 0 {
+			conds = evaluate(s, r.Name, r.Retry.Until, resp, bodyBytes, timing, shared)
+		}
+		settled = allPassed(conds)
+	}
+
+	// ----- 7. Let the caller inspect the exchange -----
+	if opts.Inspect != nil && (settled || final) {
+		results = append(results, opts.Inspect(Exchange{
+			Suite:    suite,
+			Request:  r.Name,
+			HTTPReq:  req,
+			HTTPResp: resp,
+			Body:     bodyBytes,
+		})...)
+	}
+
+	return results, received, timing, settled
+}
+
+// evaluate runs the expectations exps against a response.
+func evaluate(s *domain.TestSuite, request string, exps []domain.Expectation, resp *http.Response, body []byte, timing *httpclient.Timing, shared *sharedcontext.SharedContext) []Result {
+	var results []Result
+	for _, exp := range exps {
+		// Copy user‑provided kwargs, expanding placeholders
+		userKwargs := make(map[string]any, len(exp.Kwargs))
+		for k, v := range exp.Kwargs {
+			userKwargs[k] = templating.ExpandAny(v, shared)
+		}
+
+		// Inject auto params
+		kwargs := maps.Clone(userKwargs)
+		kwargs["status_code"] = resp.StatusCode
+		kwargs["headers"] = resp.Header
+		kwargs["suite_dir"] = suiteDir(s)
+		kwargs["response_time"] = timing.Total
+		kwargs["ttfb"] = timing.TTFB
+
+		f, ok := assert.Get(exp.Type)
+		if !ok {
+			results = append(results, Result{
+				Suite:    s.Name,
+				Request:  request,
+				Status:   StatusFailed,
+				Err:      fmt.Errorf("unknown expectation %s", exp.Type),
+				TestName: exp.Type,
+				Kwargs:   userKwargs,
+			})
+			continue
+		}
+
+		err := f(body, kwargs)
+		results = append(results, Result{
+			Suite:    s.Name,
+			Request:  request,
+			Status:   statusOf(err),
+			Err:      err,
+			TestName: exp.Type,
+			Kwargs:   userKwargs,
+		})
+	}
+	return results
+}
+
+func allPassed(results []Result) bool {
+	for _, r := range results {
+		if r.Status != StatusPassed {
+			return false
+		}
+	}
+	return true
+}
+
+// captureValues stores every capture of r in the shared context. Only failed
+// captures produce a Result, so a successful capture stays out of the report.
+func captureValues(suite string, r domain.TestRequest, resp *http.Response, body []byte, shared *sharedcontext.SharedContext) []Result {
+	var results []Result
+	for _, name := range slices.Sorted(maps.Keys(r.Capture)) {
+		v, err := capture.Extract(r.Capture[name], resp, body)
+		if err != nil {
+			results = append(results, Result{
+				Suite:    suite,
+				Request:  r.Name,
+				Status:   StatusFailed,
+				Err:      fmt.Errorf("capture %s: %w", name, err),
+				TestName: "capture",
+			})
+			continue
+		}
+		shared.Set(name, v)
+	}
+	return results
+}
+
+// suiteDir returns the directory relative file references of s are resolved against.
+func suiteDir(s *domain.TestSuite) string {
+	if s.Path == "" {
+		return ""
+	}
+	return filepath.Dir(s.Path)
 }
 
 func appendRequestErrorResults(res *[]Result, suite string, r domain.TestRequest, err error) {
@@ -112,7 +349,7 @@ func appendRequestErrorResults(res *[]Result, suite string, r domain.TestRequest
 		*res = append(*res, Result{
 			Suite:    suite,
 			Request:  r.Name,
-			Passed:   false,
+			Status:   StatusFailed,
 			Err:      err,
 			TestName: "request_error",
 		})
@@ -122,7 +359,7 @@ func appendRequestErrorResults(res *[]Result, suite string, r domain.TestRequest
 		*res = append(*res, Result{
 			Suite:    suite,
 			Request:  r.Name,
-			Passed:   false,
+			Status:   StatusFailed,
 			Err:      err,
 			TestName: exp.Type,
 		})
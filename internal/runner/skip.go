@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/condition"
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/sharedcontext"
+)
+
+// execute runs r unless skip, only or when rule it out, or a dependency in
+// blockedBy did not pass and r is not marked run_always. focus is true when
+// some suite or request of the run is marked only.
+func execute(ctx context.Context, s *domain.TestSuite, r domain.TestRequest, shared *sharedcontext.SharedContext, opts Options, focus bool, blockedBy []string) []Result {
+	reason, err := skipReason(s, r, focus, shared)
+	if err != nil {
+		var results []Result
+		appendRequestErrorResults(&results, s.Name, r, err)
+		return redact(results, shared.Secrets())
+	}
+	if reason == "" && len(blockedBy) > 0 && !r.RunAlways {
+		reason = "blocked by " + strings.Join(blockedBy, ", ")
+	}
+	if reason != "" {
+		return skippedResults(s.Name, r, reason)
+	}
+	return runRequest(ctx, s, r, shared, opts)
+}
+
+// focused reports whether any suite or request is marked only.
+func focused(suites []domain.TestSuite) bool {
+	for _, s := range suites {
+		if s.Only {
+			return true
+		}
+		for _, r := range s.Requests {
+			if r.Only {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipReason returns why r should not run, or "" when it should. when
+// conditions are evaluated against the current variables.
+func skipReason(s *domain.TestSuite, r domain.TestRequest, focus bool, shared *sharedcontext.SharedContext) (string, error) {
+	switch {
+	case s.Skip.Skipped:
+		return skipText(s.Skip, "suite is marked skip"), nil
+	case r.Skip.Skipped:
+		return skipText(r.Skip, "marked skip"), nil
+	case focus && !s.Only && !r.Only:
+		return "not marked only", nil
+	}
+	for _, when := range []string{s.When, r.When} {
+		if when == "" {
+			continue
+		}
+		ok, err := condition.Eval(when, shared)
+		if err != nil {
+			return "", fmt.Errorf("when: %w", err)
+		}
+		if !ok {
+			return fmt.Sprintf("when %q is false", when), nil
+		}
+	}
+	return "", nil
+}
+
+func skipText(s domain.Skip, def string) string {
+	if s.Reason != "" {
+		return s.Reason
+	}
+	return def
+}
+
+// skippedResults reports r as skipped: one result per expectation, or a
+// single "skip" result when it has none.
+func skippedResults(suite string, r domain.TestRequest, reason string) []Result {
+	err := errors.New(reason)
+	if len(r.Expect) == 0 {
+		return []Result{{Suite: suite, Request: r.Name, Status: StatusSkipped, Err: err, TestName: "skip"}}
+	}
+	results := make([]Result, 0, len(r.Expect))
+	for _, exp := range r.Expect {
+		results = append(results, Result{Suite: suite, Request: r.Name, Status: StatusSkipped, Err: err, TestName: exp.Type})
+	}
+	return results
+}
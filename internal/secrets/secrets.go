@@ -0,0 +1,164 @@
+// Package secrets resolves ${env:NAME} placeholders from the process
+// environment and .env files, and masks every value it handed out.
+package secrets
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Prefix marks placeholder names resolved by a Store, as in ${env:API_KEY}.
+const Prefix = "env:"
+
+// Mask replaces secret values in anything shown to the user.
+const Mask = "****"
+
+// minLen is the shortest value that gets masked. Shorter values ("1", "on")
+// would mangle unrelated output without protecting anything.
+const minLen = 4
+
+// Store looks up environment variables, falling back to values loaded from
+// .env files, and remembers every value it returned so it can be redacted.
+type Store struct {
+	mu     sync.RWMutex
+	dotenv map[string]string
+	values []string          // longest first, so overlapping secrets mask fully
+	names  map[string]string // value -> the variable it was read from
+}
+
+func New() *Store {
+	return &Store{dotenv: map[string]string{}, names: map[string]string{}}
+}
+
+// Lookup implements templating.Vars for names starting with Prefix. The
+// process environment wins over .env files.
+func (s *Store) Lookup(name string) (string, bool) {
+	key, ok := strings.CutPrefix(name, Prefix)
+	if !ok {
+		return "", false
+	}
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		s.mu.RLock()
+		v, ok = s.dotenv[key]
+		s.mu.RUnlock()
+	}
+	if ok {
+		s.Add(v)
+		if len(v) >= minLen && envName.MatchString(key) {
+			s.mu.Lock()
+			s.names[v] = key
+			s.mu.Unlock()
+		}
+	}
+	return v, ok
+}
+
+// envName matches variable names a shell can expand.
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Names maps every value read so far to the variable it came from, so output
+// meant to be run, such as a curl command, can refer to "${NAME}" instead of
+// masking the value.
+func (s *Store) Names() map[string]string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.names)
+}
+
+// Add marks value as secret. Values derived from secrets, such as an encoded
+// Authorization header or a fetched token, should be added too.
+// The URL and JSON encoded forms of value are masked as well.
+func (s *Store) Add(value string) {
+	if len(value) < minLen {
+		return
+	}
+	forms := []string{value, url.QueryEscape(value), url.PathEscape(value)}
+	if data, err := json.Marshal(value); err == nil {
+		forms = append(forms, string(data[1:len(data)-1]))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range forms {
+		if !slices.Contains(s.values, v) {
+			s.values = append(s.values, v)
+		}
+	}
+	slices.SortFunc(s.values, func(a, b string) int { return len(b) - len(a) })
+}
+
+// Redact replaces every known secret in text with Mask.
+func (s *Store) Redact(text string) string {
+	if s == nil {
+		return text
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.values {
+		text = strings.ReplaceAll(text, v, Mask)
+	}
+	return text
+}
+
+// Empty reports whether no secret has been handed out yet.
+func (s *Store) Empty() bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.values) == 0
+}
+
+// LoadDotenv reads KEY=VALUE lines from path. Blank lines and # comments are
+// skipped, an "export " prefix is allowed and values may be single or double
+// quoted. Later files override earlier ones.
+func (s *Store) LoadDotenv(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			// Unquoted values may carry a trailing comment.
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+		s.mu.Lock()
+		s.dotenv[key] = value
+		s.mu.Unlock()
+	}
+	return sc.Err()
+}
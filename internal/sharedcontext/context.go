@@ -1,25 +1,92 @@
 package sharedcontext
 
-import "sync"
+import (
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/IsmailCLN/tapir/internal/secrets"
+)
+
+// SharedContext is the run-wide variable store. Values set by one request
+// (captures, tokens, …) become available to the templates of later requests.
 type SharedContext struct {
-    mu    sync.RWMutex
-    store map[string]string
+	mu      sync.RWMutex
+	store   map[string]string
+	secrets *secrets.Store
+
+	// tokenMu is held while a token is fetched, so concurrent requests
+	// sharing a token wait for one fetch instead of each starting their own.
+	tokenMu sync.Mutex
+	tokens  map[string]token
+}
+
+type token struct {
+	value  string
+	expiry time.Time // zero: valid for the whole run
 }
 
 func New() *SharedContext {
-    return &SharedContext{store: make(map[string]string)}
+	return &SharedContext{store: make(map[string]string), secrets: secrets.New(), tokens: make(map[string]token)}
+}
+
+// UseSecrets replaces the store that resolves ${env:NAME} placeholders.
+func (sc *SharedContext) UseSecrets(s *secrets.Store) {
+	sc.mu.Lock()
+	sc.secrets = s
+	sc.mu.Unlock()
+}
+
+// Secrets returns the store that resolves ${env:NAME} placeholders.
+func (sc *SharedContext) Secrets() *secrets.Store {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.secrets
 }
 
 func (sc *SharedContext) Set(key, value string) {
-    sc.mu.Lock()
-    sc.store[key] = value
-    sc.mu.Unlock()
+	sc.mu.Lock()
+	sc.store[key] = value
+	sc.mu.Unlock()
+}
+
+// SetAll stores every entry of vars.
+func (sc *SharedContext) SetAll(vars map[string]string) {
+	sc.mu.Lock()
+	for k, v := range vars {
+		sc.store[k] = v
+	}
+	sc.mu.Unlock()
 }
 
 func (sc *SharedContext) Get(key string) (string, bool) {
-    sc.mu.RLock()
-    v, ok := sc.store[key]
-    sc.mu.RUnlock()
-    return v, ok
+	sc.mu.RLock()
+	v, ok := sc.store[key]
+	sc.mu.RUnlock()
+	return v, ok
+}
+
+// Lookup implements templating.Vars. Names starting with "env:" are
+// resolved by the secrets store; everything else comes from the context.
+func (sc *SharedContext) Lookup(name string) (string, bool) {
+	if strings.HasPrefix(name, secrets.Prefix) {
+		return sc.Secrets().Lookup(name)
+	}
+	return sc.Get(name)
+}
+
+// Token returns the token cached under key, calling fetch when there is none
+// or it has expired. fetch returns the token and when it expires.
+func (sc *SharedContext) Token(key string, fetch func() (string, time.Time, error)) (string, error) {
+	sc.tokenMu.Lock()
+	defer sc.tokenMu.Unlock()
+	if t, ok := sc.tokens[key]; ok && (t.expiry.IsZero() || time.Now().Before(t.expiry)) {
+		return t.value, nil
+	}
+	value, expiry, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	sc.tokens[key] = token{value: value, expiry: expiry}
+	return value, nil
 }
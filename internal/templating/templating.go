@@ -0,0 +1,150 @@
+// Package templating expands ${name} placeholders against a variable store.
+//
+// Supported forms:
+//
+//	${name}            value of name; left untouched when name is unknown
+//	${name:-fallback}  value of name, or fallback when name is unknown or empty
+//	$${name}           literal "${name}" (escape)
+//
+// Fallbacks may themselves contain placeholders, e.g. ${host:-${default_host}}.
+// Names are resolved by Vars; the run's store resolves ${env:NAME} from the
+// environment and .env files.
+package templating
+
+import "strings"
+
+// Vars resolves a placeholder name to its value.
+type Vars interface {
+	Lookup(name string) (string, bool)
+}
+
+// Map adapts a plain map to Vars.
+type Map map[string]string
+
+func (m Map) Lookup(name string) (string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// Expand replaces every placeholder in s using vars.
+func Expand(s string, vars Vars) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		// escape: $${...} -> ${...}
+		if strings.HasPrefix(s[i:], "$${") {
+			i++
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			b.WriteString(s[i : end+1])
+			i = end + 1
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end := closingBrace(s, i+2)
+		if end < 0 {
+			// unterminated placeholder: keep as-is
+			b.WriteString(s[i:])
+			break
+		}
+		b.WriteString(resolve(s[i:end+1], s[i+2:end], vars))
+		i = end + 1
+	}
+	return b.String()
+}
+
+// ExpandAny walks strings, maps and slices (as produced by the YAML decoder)
+// and returns a copy with every string expanded. Other values are returned as-is.
+func ExpandAny(v any, vars Vars) any {
+	switch t := v.(type) {
+	case string:
+		return Expand(t, vars)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, vv := range t {
+			out[k] = ExpandAny(vv, vars)
+		}
+		return out
+	case map[any]any:
+		out := make(map[any]any, len(t))
+		for k, vv := range t {
+			out[k] = ExpandAny(vv, vars)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, vv := range t {
+			out[i] = ExpandAny(vv, vars)
+		}
+		return out
+	case []string:
+		out := make([]string, len(t))
+		for i, vv := range t {
+			out[i] = Expand(vv, vars)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// ExpandStrings returns a copy of m with every value expanded.
+func ExpandStrings(m map[string]string, vars Vars) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = Expand(v, vars)
+	}
+	return out
+}
+
+// resolve evaluates the inner part of a single placeholder. raw is the full
+// "${...}" text, returned unchanged when the name cannot be resolved.
+func resolve(raw, inner string, vars Vars) string {
+	name, fallback, hasFallback := strings.Cut(inner, ":-")
+	name = strings.TrimSpace(name)
+
+	if vars != nil {
+		if v, ok := vars.Lookup(name); ok && (v != "" || !hasFallback) {
+			return v
+		}
+	}
+	if hasFallback {
+		return Expand(fallback, vars)
+	}
+	return raw
+}
+
+// closingBrace returns the index of the '}' matching a placeholder whose
+// content starts at from, honouring nested ${...}. It returns -1 if none.
+func closingBrace(s string, from int) int {
+	depth := 1
+	for i := from; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"github.com/IsmailCLN/tapir/internal/report"
+	"github.com/IsmailCLN/tapir/internal/runner"
+)
+
+// Summary counts the results of a headless run.
+type Summary struct {
+	Passed, Failed, Skipped int
+	Elapsed                 time.Duration
+}
+
+// RunPlain runs the suites without the TUI, writing one line per result to w
+// as soon as it arrives, followed by a summary line. It is meant for CI logs
+// and other non-interactive output. The reports are complete when it returns.
+func RunPlain(ctx context.Context, w io.Writer, paths []string, opts runner.Options, reports []report.Spec) (Summary, error) {
+	start := time.Now()
+	ch, wait, err := startRun(ctx, paths, opts, reports)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var sum Summary
+	for r := range ch {
+		fmt.Fprintln(w, plainLine(r))
+		switch r.Status {
+		case runner.StatusPassed:
+			sum.Passed++
+		case runner.StatusFailed:
+			sum.Failed++
+		case runner.StatusSkipped:
+			sum.Skipped++
+		}
+	}
+	sum.Elapsed = time.Since(start)
+
+	fmt.Fprintf(w, "\nPassed: %d  Failed: %d  Skipped: %d  Total: %d  (%s)\n",
+		sum.Passed, sum.Failed, sum.Skipped, sum.Passed+sum.Failed+sum.Skipped, sum.Elapsed.Round(time.Millisecond))
+	if err := wait(); err != nil {
+		return sum, err
+	}
+	return sum, ctx.Err()
+}
+
+func plainLine(r runner.Result) string {
+	msg := ""
+	if r.Err != nil {
+		msg = helpers.Sanitize(r.Err.Error())
+	}
+	switch r.Status {
+	case runner.StatusPassed:
+		return fmt.Sprintf("✓ %s / %s  %s (%s%s)", r.Suite, r.Request, r.TestName, formatDuration(r.Duration), formatAttempts(r.Attempts))
+	case runner.StatusSkipped:
+		return fmt.Sprintf("○ %s / %s  %s (skipped): %s", r.Suite, r.Request, r.TestName, msg)
+	}
+	if msg == "" {
+		msg = "failed"
+	}
+	return fmt.Sprintf("✗ %s / %s  %s (%s%s): %s", r.Suite, r.Request, r.TestName, formatDuration(r.Duration), formatAttempts(r.Attempts), msg)
+}
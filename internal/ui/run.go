@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/parser"
+	"github.com/IsmailCLN/tapir/internal/report"
+	"github.com/IsmailCLN/tapir/internal/runner"
+	"github.com/IsmailCLN/tapir/internal/validate"
+)
+
+// startRun loads the suites, opens the reports and starts the run. The
+// returned wait function yields report errors once the channel is drained.
+func startRun(ctx context.Context, paths []string, opts runner.Options, reports []report.Spec) (<-chan runner.Result, func() error, error) {
+	suites, err := loadSuites(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ws []report.Writer
+	for _, spec := range reports {
+		w, err := report.Open(spec)
+		if err != nil {
+			for _, w := range ws {
+				w.Close()
+			}
+			return nil, nil, err
+		}
+		ws = append(ws, w)
+	}
+
+	ch, wait := report.Tee(runner.RunConcurrent(ctx, suites, opts), ws)
+	return ch, wait, nil
+}
+
+// loadSuites runs the same checks as tapir validate before loading, so a
+// suite edited between reruns is not sent with unknown keys or a cycle.
+func loadSuites(paths []string) ([]domain.TestSuite, error) {
+	var allSuites []domain.TestSuite
+	for _, p := range paths {
+		issues, err := validate.File(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			lines := make([]string, len(issues))
+			for i, is := range issues {
+				lines[i] = is.String()
+			}
+			return nil, fmt.Errorf("%s: %d problem(s) found, nothing was sent\n%s", p, len(issues), strings.Join(lines, "\n"))
+		}
+		s, err := parser.LoadTestSuite(p)
+		if err != nil {
+			return nil, err
+		}
+		allSuites = append(allSuites, s...)
+	}
+	return allSuites, nil
+}
@@ -5,6 +5,7 @@ import "github.com/charmbracelet/lipgloss"
 var (
 	green = lipgloss.NewStyle().Foreground(lipgloss.Color("#22c55e")).Render
 	red   = lipgloss.NewStyle().Foreground(lipgloss.Color("#ef4444")).Render
+	amber = lipgloss.NewStyle().Foreground(lipgloss.Color("#eab308")).Render
 
 	PurpleColor    = lipgloss.Color("99")
 	HeaderStyle    = lipgloss.NewStyle().Foreground(PurpleColor).Bold(true).Align(lipgloss.Center)
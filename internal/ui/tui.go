@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
 
-	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/curl"
 	"github.com/IsmailCLN/tapir/internal/helpers"
-	"github.com/IsmailCLN/tapir/internal/parser"
+	"github.com/IsmailCLN/tapir/internal/report"
 	"github.com/IsmailCLN/tapir/internal/runner"
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,18 +22,24 @@ import (
 )
 
 type resultMsg struct{ r runner.Result }
-type doneMsg struct{}
-type startStreamMsg struct{ ch <-chan runner.Result }
+type doneMsg struct{ err error }
+type startStreamMsg struct {
+	ch   <-chan runner.Result
+	wait func() error
+}
 
 type resultView struct {
 	rows       [][]string
 	results    []runner.Result
 	message    string
 	suitePaths []string
+	runOpts    runner.Options
+	reports    []report.Spec
 	isRunning  bool
 	lastRerun  time.Time
 
 	resultsCh <-chan runner.Result
+	wait      func() error
 }
 
 type rerunDoneMsg struct {
@@ -43,37 +50,32 @@ type rerunDoneMsg struct {
 func (rv resultView) Init() tea.Cmd {
 	// If we already have a channel to listen to, start listening.
 	if rv.resultsCh != nil {
-		return listenResults(rv.resultsCh)
+		return listenResults(rv.resultsCh, rv.wait)
 	}
 	// If marked running without channel, kick off an initial run.
 	if rv.isRunning && len(rv.suitePaths) > 0 {
-		return startRunCmd(rv.suitePaths)
+		return startRunCmd(rv.suitePaths, rv.runOpts, rv.reports)
 	}
 	return nil
 }
 
-func listenResults(ch <-chan runner.Result) tea.Cmd {
+func listenResults(ch <-chan runner.Result, wait func() error) tea.Cmd {
 	return func() tea.Msg {
 		r, ok := <-ch
 		if !ok {
-			return doneMsg{}
+			return doneMsg{err: wait()}
 		}
 		return resultMsg{r: r}
 	}
 }
 
-func startRunCmd(paths []string) tea.Cmd {
+func startRunCmd(paths []string, opts runner.Options, reports []report.Spec) tea.Cmd {
 	return func() tea.Msg {
-		var allSuites []domain.TestSuite
-		for _, p := range paths {
-			s, err := parser.LoadTestSuite(p)
-			if err != nil {
-				return rerunDoneMsg{err: fmt.Errorf("reload error: %w", err)}
-			}
-			allSuites = append(allSuites, s...)
+		ch, wait, err := startRun(context.Background(), paths, opts, reports)
+		if err != nil {
+			return rerunDoneMsg{err: fmt.Errorf("reload error: %w", err)}
 		}
-		ch := runner.RunConcurrent(context.Background(), allSuites, runner.Options{})
-		return startStreamMsg{ch: ch}
+		return startStreamMsg{ch: ch, wait: wait}
 	}
 }
 
@@ -82,22 +84,23 @@ func (rv resultView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case startStreamMsg:
 		// Channel ready, start listening.
 		rv.resultsCh = m.ch
+		rv.wait = m.wait
 		rv.rows = nil
 		rv.results = nil
 		rv.message = checkIOErr("Running…", nil)
 		rv.isRunning = true
-		return rv, listenResults(rv.resultsCh)
+		return rv, listenResults(rv.resultsCh, rv.wait)
 
 	case resultMsg:
 		// One result arrived; append row and keep listening.
 		rv.results = append(rv.results, m.r)
 		rv.rows = append(rv.rows, buildRow(m.r))
-		return rv, listenResults(rv.resultsCh)
+		return rv, listenResults(rv.resultsCh, rv.wait)
 
 	case doneMsg:
 		rv.isRunning = false
 		rv.lastRerun = time.Now()
-		rv.message = checkIOErr("Completed at "+rv.lastRerun.Format("15:04:05"), nil)
+		rv.message = checkIOErr("Completed at "+rv.lastRerun.Format("15:04:05"), m.err)
 		return rv, nil
 
 	case rerunDoneMsg:
@@ -126,7 +129,9 @@ func (rv resultView) keyHandlers() map[string]func(resultView) (tea.Model, tea.C
 	return map[string]func(resultView) (tea.Model, tea.Cmd){
 		"r":      handleRerun,
 		"c":      handleCopy,
+		"x":      handleCopyCurl,
 		"p":      handleSaveMarkdown,
+		"h":      handleSaveHTML,
 		"q":      handleQuit,
 		"esc":    handleQuit,
 		"ctrl+c": handleQuit,
@@ -141,6 +146,17 @@ func handleCopy(rv resultView) (tea.Model, tea.Cmd) {
 	return rv, nil
 }
 
+func handleCopyCurl(rv resultView) (tea.Model, tea.Cmd) {
+	out := rv.getCurlOutput()
+	if out == "" {
+		rv.message = checkIOErr("No requests to copy yet", errors.New("empty"))
+		return rv, nil
+	}
+	err := clipboard.WriteAll(out)
+	rv.message = checkIOErr("curl commands copied to clipboard", err)
+	return rv, nil
+}
+
 func handleSaveMarkdown(rv resultView) (tea.Model, tea.Cmd) {
 	filename := "tapir-report-" + time.Now().Format("20060102") + ".md"
 	err := os.WriteFile(filename, []byte(rv.getMarkdownOutput()), 0644)
@@ -148,6 +164,13 @@ func handleSaveMarkdown(rv resultView) (tea.Model, tea.Cmd) {
 	return rv, nil
 }
 
+func handleSaveHTML(rv resultView) (tea.Model, tea.Cmd) {
+	filename := "tapir-report-" + time.Now().Format("20060102") + ".html"
+	err := report.WriteFile(report.Spec{Format: "html", Path: filename}, rv.results)
+	rv.message = checkIOErr("HTML report saved to "+filename, err)
+	return rv, nil
+}
+
 func handleRerun(rv resultView) (tea.Model, tea.Cmd) {
 	if rv.isRunning {
 		rv.message = checkIOErr("Already running, please wait…", errors.New("busy"))
@@ -160,7 +183,7 @@ func handleRerun(rv resultView) (tea.Model, tea.Cmd) {
 
 	rv.isRunning = true
 	rv.message = checkIOErr("Re-running…", nil)
-	return rv, startRunCmd(rv.suitePaths)
+	return rv, startRunCmd(rv.suitePaths, rv.runOpts, rv.reports)
 }
 
 func (rv resultView) View() string {
@@ -168,11 +191,11 @@ func (rv resultView) View() string {
 		Border(lgl.NormalBorder()).
 		BorderStyle(lgl.NewStyle().Foreground(PurpleColor)).
 		StyleFunc(styleCell).
-		Headers("✓", "Suite", "Request", "Test", "Error").
+		Headers("✓", "Suite", "Request", "Test", "Time", "Error").
 		Rows(rv.rows...)
 
 	return lgl.NewStyle().Margin(1, 2).
-		Render("🧪 Tapir Test Results\n\n" + t.String() + "\nPress 'c' to copy, 'p' to save as markdown, 'r' to rerun, 'q' to quit.\n\n" + rv.message)
+		Render("🧪 Tapir Test Results\n\n" + t.String() + "\nPress 'c' to copy, 'x' to copy as curl, 'p' to save as markdown, 'h' as HTML, 'r' to rerun, 'q' to quit.\n\n" + rv.message)
 }
 
 // –– Helpers –– //
@@ -186,6 +209,8 @@ func styleCell(row, col int) lgl.Style {
 	case col == 1, col == 2, col == 3:
 		s = LargeCell
 	case col == 4:
+		s = MediumCell.Align(lgl.Right)
+	case col == 5:
 		s = ExtraLargeCell
 	default:
 		s = MediumCell
@@ -204,8 +229,11 @@ func styleCell(row, col int) lgl.Style {
 
 func buildRow(r runner.Result) []string {
 	icon := green("✓")
-	if !r.Passed {
+	switch r.Status {
+	case runner.StatusFailed:
 		icon = red("✗")
+	case runner.StatusSkipped:
+		icon = amber("○")
 	}
 
 	errMsg := ""
@@ -218,10 +246,31 @@ func buildRow(r runner.Result) []string {
 		r.Suite,
 		r.Request,
 		r.TestName,
+		formatDuration(r.Duration) + formatAttempts(r.Attempts),
 		errMsg,
 	}
 }
 
+// formatAttempts marks results of retried requests, e.g. " ×3".
+func formatAttempts(n int) string {
+	if n <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" ×%d", n)
+}
+
+// formatDuration renders request durations compactly; zero means nothing was sent.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "–"
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
 func buildRows(results []runner.Result) [][]string {
 	rows := make([][]string, len(results))
 	for i, r := range results {
@@ -241,11 +290,15 @@ func Render(paths []string, results []runner.Result) error {
 	return err
 }
 
-func RenderStream(paths []string) error {
+// RenderStream runs the suites in the TUI. The reports are rewritten on
+// every (re)run.
+func RenderStream(paths []string, opts runner.Options, reports []report.Spec) error {
 	rv := resultView{
 		rows:       nil,
 		results:    nil,
 		suitePaths: paths,
+		runOpts:    opts,
+		reports:    reports,
 		isRunning:  true,
 		message:    checkIOErr("Running…", nil),
 	}
@@ -257,18 +310,22 @@ func RenderStream(paths []string) error {
 func (rv resultView) getRawOutput() string {
 	var b strings.Builder
 	const minColWidth = 4
-	var passed, failed int
+	var passed, failed, skipped int
 
 	w := tabwriter.NewWriter(&b, minColWidth, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "✓\tSuite\tRequest\tTest\tError")
 
 	for _, r := range rv.results {
-		icon := "✗"
-		if r.Passed {
-			icon = "✓"
+		icon := "✓"
+		switch r.Status {
+		case runner.StatusPassed:
 			passed++
-		} else {
+		case runner.StatusFailed:
+			icon = "✗"
 			failed++
+		case runner.StatusSkipped:
+			icon = "○"
+			skipped++
 		}
 
 		errMsg := ""
@@ -286,7 +343,7 @@ func (rv resultView) getRawOutput() string {
 	}
 
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "Passed:\t%d\tFailed:\t%d\tTotal:\t%d\n", passed, failed, passed+failed)
+	fmt.Fprintf(w, "Passed:\t%d\tFailed:\t%d\tSkipped:\t%d\tTotal:\t%d\n", passed, failed, skipped, passed+failed+skipped)
 
 	_ = w.Flush()
 
@@ -298,7 +355,7 @@ func (rv resultView) getRawOutput() string {
 
 func (rv resultView) getMarkdownOutput() string {
 	var sb strings.Builder
-	var passed, failed int
+	var passed, failed, skipped int
 
 	sb.WriteString("# 🧪 Tapir Test Results\n\n")
 	sb.WriteString("| ✓ | Suite | Request | Test Name | Description |\n")
@@ -306,11 +363,15 @@ func (rv resultView) getMarkdownOutput() string {
 
 	for _, r := range rv.results {
 		icon := "✓"
-		if r.Passed {
+		switch r.Status {
+		case runner.StatusPassed:
 			passed++
-		} else {
+		case runner.StatusFailed:
 			icon = "✗"
 			failed++
+		case runner.StatusSkipped:
+			icon = "○"
+			skipped++
 		}
 
 		errMsg := ""
@@ -321,7 +382,28 @@ func (rv resultView) getMarkdownOutput() string {
 		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
 			icon, r.Suite, r.Request, r.TestName, errMsg))
 	}
-	sb.WriteString(fmt.Sprintf("\n**Summary:** ✅ %d passed, ❌ %d failed\n", passed, failed))
+	sb.WriteString(fmt.Sprintf("\n**Summary:** ✅ %d passed, ❌ %d failed, ⏭️ %d skipped\n", passed, failed, skipped))
 
 	return sb.String()
 }
+
+// getCurlOutput renders the requests behind failed results as curl commands,
+// or every request when nothing failed. Each request appears once.
+func (rv resultView) getCurlOutput() string {
+	failedOnly := slices.ContainsFunc(rv.results, func(r runner.Result) bool { return r.Status == runner.StatusFailed && r.Sent != nil })
+
+	var b strings.Builder
+	seen := map[*runner.RequestInfo]bool{}
+	for _, r := range rv.results {
+		if r.Sent == nil || seen[r.Sent] || failedOnly && r.Status != runner.StatusFailed {
+			continue
+		}
+		seen[r.Sent] = true
+		cmd := r.Sent.Curl
+		if cmd == "" {
+			cmd = curl.Format(r.Sent.Method, r.Sent.URL, r.Sent.Headers, r.Sent.Body)
+		}
+		fmt.Fprintf(&b, "# %s / %s\n%s\n\n", r.Suite, r.Request, cmd)
+	}
+	return b.String()
+}
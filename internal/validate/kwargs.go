@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/assert"
+	"github.com/IsmailCLN/tapir/internal/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+// kwarg checks one kwarg of an assertion against its parameter spec. Values
+// are coerced the way the assertions do, so "200" is a valid integer.
+// Strings with placeholders are only known at run time and pass.
+func (c *checker) kwarg(assertion string, params []assert.Param, key, val *yaml.Node) {
+	var spec *assert.Param
+	names := make([]string, len(params))
+	for i := range params {
+		names[i] = params[i].Name
+		if params[i].Name == key.Value {
+			spec = &params[i]
+		}
+	}
+	if spec == nil {
+		msg := fmt.Sprintf("%s: unknown kwarg %q", assertion, key.Value)
+		if s := suggest(key.Value, names); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		c.addf(key, "%s", msg)
+		return
+	}
+
+	if val.Kind == yaml.ScalarNode && strings.Contains(val.Value, "${") {
+		return
+	}
+	if !typeMatches(spec.Type, val) {
+		c.addf(val, "%s: kwarg %q must be %s", assertion, key.Value, article(spec.Type))
+	}
+}
+
+func typeMatches(t assert.Type, n *yaml.Node) bool {
+	switch t {
+	case assert.TypeList:
+		return n.Kind == yaml.SequenceNode
+	case assert.TypeMap:
+		return n.Kind == yaml.MappingNode
+	case assert.TypeAny:
+		return true
+	}
+	if n.Kind != yaml.ScalarNode {
+		return false
+	}
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return false
+	}
+	var err error
+	switch t {
+	case assert.TypeString:
+		_, err = helpers.AsString(v)
+	case assert.TypeInt:
+		_, err = helpers.AsInt(v)
+	case assert.TypeNumber:
+		_, err = helpers.AsFloat64(v)
+	case assert.TypeBool:
+		_, err = helpers.AsBool(v)
+	case assert.TypeDuration:
+		_, err = helpers.AsDuration(v)
+	}
+	return err == nil
+}
+
+func article(t assert.Type) string {
+	switch t {
+	case assert.TypeInt:
+		return "an integer"
+	case assert.TypeDuration:
+		return "a duration such as 250ms or 1.5s"
+	}
+	return "a " + string(t)
+}
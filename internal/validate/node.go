@@ -0,0 +1,46 @@
+package validate
+
+import "gopkg.in/yaml.v3"
+
+// resolve follows aliases to the node they refer to.
+func resolve(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// pairs returns the key/value pairs of a mapping, with merge keys ("<<")
+// expanded. Keys set directly win over merged ones.
+func pairs(m *yaml.Node) [][2]*yaml.Node {
+	m = resolve(m)
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	var own, merged [][2]*yaml.Node
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i], resolve(m.Content[i+1])
+		if k.Tag == "!!merge" {
+			if v.Kind == yaml.SequenceNode {
+				for _, src := range v.Content {
+					merged = append(merged, pairs(src)...)
+				}
+			} else {
+				merged = append(merged, pairs(v)...)
+			}
+			continue
+		}
+		own = append(own, [2]*yaml.Node{k, v})
+	}
+	return append(own, merged...)
+}
+
+// lookup returns the value of key in mapping m, or nil.
+func lookup(m *yaml.Node, key string) *yaml.Node {
+	for _, p := range pairs(m) {
+		if p[0].Value == key {
+			return p[1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var unmarshalerType = reflect.TypeFor[yaml.Unmarshaler]()
+
+// schema checks n against the type it is decoded into: unknown keys, lists
+// and mappings where they belong, and scalars that do not decode. Values
+// typed any (body, kwargs, ..) are not looked into.
+func (c *checker) schema(n *yaml.Node, t reflect.Type, key string) {
+	n = resolve(n)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		if err := n.Decode(reflect.New(t).Interface()); err != nil {
+			msg := decodeError(err)
+			if !strings.HasPrefix(msg, key+" ") {
+				msg = key + ": " + msg
+			}
+			c.addf(n, "%s", msg)
+			return
+		}
+		if t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode {
+			c.fields(n, t)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			c.addf(n, "%s: expected a mapping", key)
+			return
+		}
+		c.fields(n, t)
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			c.addf(n, "%s: expected a list", key)
+			return
+		}
+		for _, item := range n.Content {
+			c.schema(item, t.Elem(), key)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			c.addf(n, "%s: expected a mapping", key)
+			return
+		}
+		for _, p := range pairs(n) {
+			c.schema(p[1], t.Elem(), key+"."+p[0].Value)
+		}
+	default:
+		if n.Kind != yaml.ScalarNode {
+			c.addf(n, "%s: expected a %s", key, t.Kind())
+			return
+		}
+		if err := n.Decode(reflect.New(t).Interface()); err != nil {
+			c.addf(n, "%s: %s", key, decodeError(err))
+		}
+	}
+}
+
+// fields checks the keys of mapping n against struct t.
+func (c *checker) fields(n *yaml.Node, t reflect.Type) {
+	known := yamlFields(t)
+	for _, p := range pairs(n) {
+		k := p[0].Value
+		f, ok := known[k]
+		if !ok {
+			msg := fmt.Sprintf("unknown key %q", k)
+			if s := suggest(k, slices.Sorted(maps.Keys(known))); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			c.addf(p[0], "%s", msg)
+			continue
+		}
+		c.schema(p[1], f, k)
+	}
+}
+
+// yamlFields maps the YAML keys of struct t to their types, following
+// inline fields the way yaml.v3 does.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	out := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if slices.Contains(strings.Split(opts, ","), "inline") {
+			for k, v := range yamlFields(f.Type) {
+				out[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		out[name] = f.Type
+	}
+	return out
+}
+
+var linePrefix = regexp.MustCompile(`^(yaml: )?line \d+: `)
+
+// decodeError strips the position yaml.v3 puts in its messages, since the
+// issue carries its own.
+func decodeError(err error) string {
+	var te *yaml.TypeError
+	msgs := []string{err.Error()}
+	if errors.As(err, &te) {
+		msgs = te.Errors
+	}
+	for i, m := range msgs {
+		msgs[i] = linePrefix.ReplaceAllString(m, "")
+	}
+	return strings.Join(msgs, "; ")
+}
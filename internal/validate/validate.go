@@ -0,0 +1,335 @@
+// Package validate checks suite files before anything is sent: unknown
+// keys, dependency cycles, duplicate request names, unknown expectation
+// types, kwargs that do not match the assertion and malformed URLs. It works
+// on the YAML node tree so every issue carries the line and column it
+// refers to.
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/IsmailCLN/tapir/internal/assert"
+	"github.com/IsmailCLN/tapir/internal/domain"
+	"github.com/IsmailCLN/tapir/internal/templating"
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is one problem found in a suite file.
+type Issue struct {
+	File         string
+	Line, Column int
+	Message      string
+}
+
+// String formats the issue as file:line:col: message.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// File checks the suite file at path and returns its issues in file order.
+// The error is non-nil only if the file cannot be read.
+func File(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Issue{syntaxIssue(path, err)}, nil
+	}
+
+	c := &checker{file: path}
+	if len(doc.Content) > 0 {
+		root := resolve(doc.Content[0])
+		c.schema(root, reflect.TypeFor[[]domain.TestSuite](), "suites")
+		c.suites(root)
+	}
+	slices.SortStableFunc(c.issues, func(a, b Issue) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return c.issues, nil
+}
+
+var syntaxLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// syntaxIssue turns a YAML syntax error into an issue. yaml.v3 only reports
+// the line.
+func syntaxIssue(path string, err error) Issue {
+	is := Issue{File: path, Line: 1, Column: 1, Message: err.Error()}
+	if m := syntaxLine.FindStringSubmatch(is.Message); m != nil {
+		is.Line, _ = strconv.Atoi(m[1])
+		is.Message = strings.TrimPrefix(is.Message, m[0])
+	}
+	return is
+}
+
+type checker struct {
+	file   string
+	issues []Issue
+}
+
+func (c *checker) addf(n *yaml.Node, format string, args ...any) {
+	c.issues = append(c.issues, Issue{File: c.file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+}
+
+// suites runs the checks that go beyond the shape of the file. Nodes of the
+// wrong kind are skipped here; the schema check reports them.
+func (c *checker) suites(root *yaml.Node) {
+	if root.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, s := range root.Content {
+		if s = resolve(s); s.Kind == yaml.MappingNode {
+			c.suite(s)
+		}
+	}
+}
+
+// request is what the cycle check needs to know about a request.
+type request struct {
+	name *yaml.Node
+	deps []*yaml.Node
+}
+
+func (c *checker) suite(s *yaml.Node) {
+	if base := lookup(s, "base_url"); base != nil {
+		c.url(base, true)
+	}
+
+	reqs := lookup(s, "requests")
+	if reqs == nil || reqs.Kind != yaml.SequenceNode {
+		return
+	}
+
+	var order []string
+	byName := map[string]*request{}
+	for _, r := range reqs.Content {
+		if r = resolve(r); r.Kind != yaml.MappingNode {
+			continue
+		}
+		req := c.request(r)
+		if req.name == nil {
+			continue
+		}
+		if first, ok := byName[req.name.Value]; ok {
+			c.addf(req.name, "duplicate request name %q (first defined at line %d)", req.name.Value, first.name.Line)
+			continue
+		}
+		byName[req.name.Value] = req
+		order = append(order, req.name.Value)
+	}
+
+	for _, name := range order {
+		for _, dep := range byName[name].deps {
+			if _, ok := byName[dep.Value]; !ok {
+				c.addf(dep, "depends_on references unknown request %q", dep.Value)
+			}
+		}
+	}
+	c.cycles(order, byName)
+}
+
+func (c *checker) request(r *yaml.Node) *request {
+	req := &request{}
+	if name := lookup(r, "name"); name != nil && name.Kind == yaml.ScalarNode && name.Value != "" {
+		req.name = name
+	} else {
+		c.addf(r, "request has no name")
+	}
+
+	if http := lookup(r, "request"); http == nil {
+		c.addf(r, "request %q has no request block", nameOf(req))
+	} else if u := lookup(http, "url"); u == nil || u.Value == "" {
+		c.addf(http, "request %q has no url", nameOf(req))
+	} else {
+		c.url(u, false)
+	}
+
+	if exps := lookup(r, "expect"); exps != nil {
+		c.expectations(exps)
+	}
+	if retry := lookup(r, "retry"); retry != nil {
+		if until := lookup(retry, "until"); until != nil {
+			c.expectations(until)
+		}
+	}
+
+	if deps := lookup(r, "depends_on"); deps != nil && deps.Kind == yaml.SequenceNode {
+		for _, d := range deps.Content {
+			req.deps = append(req.deps, resolve(d))
+		}
+	}
+	return req
+}
+
+func nameOf(r *request) string {
+	if r.name == nil {
+		return ""
+	}
+	return r.name.Value
+}
+
+func (c *checker) expectations(list *yaml.Node) {
+	if list.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, e := range list.Content {
+		if e = resolve(e); e.Kind == yaml.MappingNode {
+			c.expectation(e)
+		}
+	}
+}
+
+func (c *checker) expectation(e *yaml.Node) {
+	typ := lookup(e, "expectation_type")
+	if typ == nil || typ.Value == "" {
+		c.addf(e, "expectation has no expectation_type")
+		return
+	}
+	params, ok := assert.Params(typ.Value)
+	if !ok {
+		msg := fmt.Sprintf("unknown expectation type %q", typ.Value)
+		if s := suggest(typ.Value, assert.Names()); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		c.addf(typ, "%s", msg)
+		return
+	}
+
+	given := map[string]bool{}
+	if kw := lookup(e, "kwargs"); kw != nil && kw.Kind == yaml.MappingNode {
+		for _, p := range pairs(kw) {
+			given[p[0].Value] = true
+			c.kwarg(typ.Value, params, p[0], p[1])
+		}
+	}
+
+	groups := map[string][]string{}
+	var order []string
+	for _, p := range params {
+		switch {
+		case p.Required && !given[p.Name]:
+			c.addf(typ, "%s: missing required kwarg %q", typ.Value, p.Name)
+		case p.Group != "":
+			if _, ok := groups[p.Group]; !ok {
+				order = append(order, p.Group)
+			}
+			groups[p.Group] = append(groups[p.Group], p.Name)
+		}
+	}
+	for _, g := range order {
+		if !slices.ContainsFunc(groups[g], func(name string) bool { return given[name] }) {
+			c.addf(typ, "%s: missing kwarg, one of %s is required", typ.Value, strings.Join(groups[g], ", "))
+		}
+	}
+}
+
+// url reports URLs that cannot be sent. Placeholders are filled in with a
+// dummy value first; relative URLs are fine since a base_url may come from
+// the suite or the environment, unless the URL is itself a base.
+func (c *checker) url(n *yaml.Node, base bool) {
+	raw := templating.Expand(n.Value, anyVar{})
+	u, err := url.Parse(raw)
+	if err != nil {
+		c.addf(n, "malformed URL %q: %v", n.Value, unwrapURLError(err))
+		return
+	}
+	switch {
+	case u.Scheme == "" && base:
+		c.addf(n, "base_url %q is not absolute (missing http:// or https://?)", n.Value)
+	case u.Scheme == "":
+	case u.Scheme != "http" && u.Scheme != "https" && !strings.Contains(n.Value, "${"):
+		c.addf(n, "malformed URL %q: unsupported scheme %q (missing http://?)", n.Value, u.Scheme)
+	case u.Host == "":
+		c.addf(n, "malformed URL %q: missing host", n.Value)
+	}
+}
+
+func unwrapURLError(err error) error {
+	if ue, ok := err.(*url.Error); ok {
+		return ue.Err
+	}
+	return err
+}
+
+// anyVar resolves every placeholder to a value that is valid anywhere in a URL.
+type anyVar struct{}
+
+func (anyVar) Lookup(string) (string, bool) { return "x", true }
+
+// cycles reports each depends_on cycle once, at the request that closes it.
+func (c *checker) cycles(order []string, reqs map[string]*request) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var stack []string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range reqs[name].deps {
+			if _, ok := reqs[dep.Value]; !ok {
+				continue
+			}
+			switch state[dep.Value] {
+			case unvisited:
+				visit(dep.Value)
+			case visiting:
+				i := slices.Index(stack, dep.Value)
+				path := append(slices.Clone(stack[i:]), dep.Value)
+				c.addf(dep, "depends_on cycle: %s", strings.Join(path, " -> "))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+	for _, name := range order {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+}
+
+// suggest returns the candidate closest to s, if it is close enough to be a typo.
+func suggest(s string, candidates []string) string {
+	best, bestDist := "", 4
+	for _, c := range candidates {
+		if d := distance(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}